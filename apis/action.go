@@ -0,0 +1,43 @@
+package apis
+
+import (
+	"fmt"
+	"szinx/core"
+	"szinx/pb"
+	"time"
+
+	"github.com/YungMonk/zinx/ziface"
+	"github.com/YungMonk/zinx/znet"
+	"github.com/golang/protobuf/proto"
+)
+
+// ActionAPI 玩家动作的路由业务
+type ActionAPI struct {
+	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
+}
+
+// Handle 处理 Connection 主业务的钩子方法 Hook
+func (a *ActionAPI) Handle(request ziface.IRequest) {
+	// 1.解析客户端传递的proto协议
+	protoMsg := &pb.Action{}
+	if err := proto.Unmarshal(request.GetData(), protoMsg); err != nil {
+		fmt.Println("Action proto unmarshal err:", err)
+		return
+	}
+
+	// 2.获取当前做出动作的是哪个玩家
+	player := loginPlayer(a.World, request)
+	if player == nil {
+		return
+	}
+
+	// 3.校验动作并广播给视野内的玩家，失败时告知客户端原因
+	remain, err := player.DoAction(protoMsg.ActionID, time.Now())
+	if err != nil {
+		fmt.Printf("Player pid=%d action %d failed: %s\n", player.Pid, protoMsg.ActionID, err)
+		player.SendActionResult(protoMsg.ActionID, remain, err)
+	}
+}
@@ -0,0 +1,72 @@
+package apis
+
+import (
+	"fmt"
+	"szinx/core"
+	"szinx/pb"
+
+	"github.com/YungMonk/zinx/ziface"
+	"github.com/YungMonk/zinx/znet"
+	"github.com/golang/protobuf/proto"
+)
+
+// InstanceAPI 队伍创建副本以及进入副本的路由业务
+type InstanceAPI struct {
+	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
+}
+
+// Handle 处理 Connection 主业务的钩子方法 Hook
+func (i *InstanceAPI) Handle(request ziface.IRequest) {
+	// 1.解析客户端传递的proto协议
+	protoMsg := &pb.EnterInstance{}
+	if err := proto.Unmarshal(request.GetData(), protoMsg); err != nil {
+		fmt.Println("EnterInstance proto unmarshal err:", err)
+		return
+	}
+
+	// 2.获取当前请求进入副本的是哪个玩家
+	player := loginPlayer(i.World, request)
+	if player == nil {
+		return
+	}
+
+	// 3.有模板ID时创建新的副本并进入，否则进入队伍已经创建的副本
+	var err error
+	if protoMsg.TemplateID != 0 {
+		_, err = i.World.InstanceMgr.CreatePartyInstance(player, protoMsg.TemplateID, protoMsg.Members)
+	} else {
+		err = i.World.InstanceMgr.JoinInstance(player, protoMsg.SceneID)
+	}
+
+	// 4.进入副本失败时告知客户端原因，成功时传送已经发送了结果
+	if err != nil {
+		fmt.Printf("Player pid=%d enter instance failed: %s\n", player.Pid, err)
+		player.SendTeleportResult(err)
+	}
+}
+
+// LeaveInstanceAPI 玩家离开所在副本的路由业务
+type LeaveInstanceAPI struct {
+	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
+}
+
+// Handle 处理 Connection 主业务的钩子方法 Hook
+func (l *LeaveInstanceAPI) Handle(request ziface.IRequest) {
+	// 1.获取当前请求离开副本的是哪个玩家
+	player := loginPlayer(l.World, request)
+	if player == nil {
+		return
+	}
+
+	// 2.离开副本失败时告知客户端原因，成功时传送已经发送了结果
+	if err := l.World.InstanceMgr.LeaveInstance(player); err != nil {
+		fmt.Printf("Player pid=%d leave instance failed: %s\n", player.Pid, err)
+		player.SendTeleportResult(err)
+	}
+}
@@ -0,0 +1,83 @@
+package apis
+
+import (
+	"fmt"
+	"szinx/core"
+	"szinx/pb"
+
+	"github.com/YungMonk/zinx/ziface"
+	"github.com/YungMonk/zinx/znet"
+	"github.com/golang/protobuf/proto"
+)
+
+// LoginAPI 登录的路由业务
+type LoginAPI struct {
+	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
+}
+
+// Handle 处理 Connection 主业务的钩子方法 Hook
+func (l *LoginAPI) Handle(request ziface.IRequest) {
+	// 1.解析客户端传递的proto协议
+	protoMsg := &pb.Login{}
+	if err := proto.Unmarshal(request.GetData(), protoMsg); err != nil {
+		fmt.Println("Login proto unmarshal err:", err)
+		return
+	}
+
+	// 2.校验登录凭证并创建玩家，失败时告知客户端原因
+	conn := request.GetConnection()
+	player, err := l.World.Login(conn, protoMsg.Account, protoMsg.Token)
+	if err != nil {
+		fmt.Printf("Connection %d login account %q failed: %s\n", conn.GetConnID(), protoMsg.Account, err)
+		core.SendLoginResult(conn, nil, err)
+		return
+	}
+
+	fmt.Println("\n====> Player pid=", player.Pid, " is arrived ====")
+}
+
+// ResumeAPI 断线重连之后恢复会话的路由业务
+type ResumeAPI struct {
+	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
+}
+
+// Handle 处理 Connection 主业务的钩子方法 Hook
+func (r *ResumeAPI) Handle(request ziface.IRequest) {
+	// 1.解析客户端传递的proto协议
+	protoMsg := &pb.Resume{}
+	if err := proto.Unmarshal(request.GetData(), protoMsg); err != nil {
+		fmt.Println("Resume proto unmarshal err:", err)
+		return
+	}
+
+	// 2.将当前连接绑定到会话对应的玩家，失败时告知客户端原因，客户端需要重新登录
+	conn := request.GetConnection()
+	player, err := r.World.Resume(conn, protoMsg.Session)
+	if err != nil {
+		fmt.Printf("Connection %d resume session failed: %s\n", conn.GetConnID(), err)
+		core.SendLoginResult(conn, nil, err)
+		return
+	}
+
+	fmt.Println("\n====> Player pid=", player.Pid, " is resumed ====")
+}
+
+// loginPlayer 获取请求的连接在世界中绑定的玩家，连接还没有登录时告知客户端并返回 nil
+func loginPlayer(world *core.WorldManager, request ziface.IRequest) *core.Player {
+	conn := request.GetConnection()
+
+	pid, err := conn.GetProperty("pid")
+	if err != nil {
+		fmt.Printf("Connection %d is not logged in, msgID=%d rejected\n", conn.GetConnID(), request.GetMsgID())
+		core.SendLoginResult(conn, nil, core.ErrNotLoggedIn)
+		return nil
+	}
+
+	return world.GetPlayerByPid(pid.(int32))
+}
@@ -4,15 +4,19 @@ import (
 	"fmt"
 	"szinx/core"
 	"szinx/pb"
+	"time"
 
 	"github.com/YungMonk/zinx/ziface"
 	"github.com/YungMonk/zinx/znet"
-	"google.golang.org/protobuf/proto"
+	"github.com/golang/protobuf/proto"
 )
 
 // MoveAPI 玩家移动的路由业务
 type MoveAPI struct {
 	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
 }
 
 // Handle 处理 Connection 主业务的钩子方法 Hook
@@ -26,26 +30,69 @@ func (m *MoveAPI) Handle(request ziface.IRequest) {
 	}
 
 	// 2.获取当前发送位置信息的是哪个玩家
-	pid, err := request.GetConnection().GetProperty("pid")
-	if err != nil {
-		fmt.Printf("pid not found")
+	player := loginPlayer(m.World, request)
+	if player == nil {
 		return
 	}
 	fmt.Printf(
 		"Player pid=%d，move(%f,%f,%f,%f)",
-		pid,
+		player.Pid,
 		positionProtoMsg.X,
 		positionProtoMsg.Y,
 		positionProtoMsg.Z,
 		positionProtoMsg.V,
 	)
-	player := core.WorldMgrObj.GetPlayerByPid(pid.(int32))
 
-	// 3.更新当前玩家的坐标，并广播给周边的玩家（九宫格内的玩家）
-	player.UpdatePos(
+	// 3.校验移动是否合法，更新当前玩家的坐标，并广播给周边的玩家（九宫格内的玩家）
+	if err := player.HandleMove(
 		positionProtoMsg.X,
 		positionProtoMsg.Y,
 		positionProtoMsg.Z,
 		positionProtoMsg.V,
-	)
+		time.Now(),
+	); err != nil {
+		// 4.移动被拒绝或者坐标被限制在边界内时，告知客户端纠正后的坐标
+		fmt.Printf("Player pid=%d move corrected: %s\n", player.Pid, err)
+		player.SendMoveCorrection(err)
+	}
+}
+
+// SeqMoveAPI 玩家带序号移动的路由业务，处理之后给客户端回复确认
+type SeqMoveAPI struct {
+	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
+}
+
+// Handle 处理 Connection 主业务的钩子方法 Hook
+func (m *SeqMoveAPI) Handle(request ziface.IRequest) {
+	// 1.解析客户端传递的proto协议
+	protoMsg := &pb.Move{}
+	if err := proto.Unmarshal(request.GetData(), protoMsg); err != nil {
+		fmt.Println("Move proto unmarshal err:", err)
+		return
+	}
+	if protoMsg.P == nil {
+		fmt.Println("Move position is empty")
+		return
+	}
+
+	// 2.获取当前发送位置信息的是哪个玩家
+	player := loginPlayer(m.World, request)
+	if player == nil {
+		return
+	}
+
+	// 3.校验并执行移动，结果通过 MsgID:207 确认给客户端
+	if err := player.HandleSeqMove(
+		protoMsg.Seq,
+		protoMsg.P.X,
+		protoMsg.P.Y,
+		protoMsg.P.Z,
+		protoMsg.P.V,
+		time.Now(),
+	); err != nil {
+		fmt.Printf("Player pid=%d move seq=%d: %s\n", player.Pid, protoMsg.Seq, err)
+	}
 }
@@ -0,0 +1,58 @@
+package apis
+
+import (
+	"fmt"
+	"szinx/core"
+	"szinx/pb"
+
+	"github.com/YungMonk/zinx/ziface"
+	"github.com/YungMonk/zinx/znet"
+	"github.com/golang/protobuf/proto"
+)
+
+// TeleportAPI 玩家传送的路由业务
+type TeleportAPI struct {
+	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
+}
+
+// Handle 处理 Connection 主业务的钩子方法 Hook
+func (t *TeleportAPI) Handle(request ziface.IRequest) {
+	// 1.解析客户端传递的proto协议
+	protoMsg := &pb.Teleport{}
+	if err := proto.Unmarshal(request.GetData(), protoMsg); err != nil {
+		fmt.Println("Teleport proto unmarshal err:", err)
+		return
+	}
+	// 2.获取当前请求传送的是哪个玩家
+	player := loginPlayer(t.World, request)
+	if player == nil {
+		return
+	}
+
+	// 3.使用传送门时由服务器选择目的地，否则需要权限才能传送到客户端指定的坐标
+	var err error
+	switch {
+	case protoMsg.PortalID != 0:
+		err = player.UsePortal(protoMsg.PortalID)
+	case protoMsg.P == nil:
+		fmt.Println("Teleport position is empty")
+		return
+	default:
+		err = player.RequestTeleport(
+			protoMsg.SceneID,
+			protoMsg.P.X,
+			protoMsg.P.Y,
+			protoMsg.P.Z,
+			protoMsg.P.V,
+		)
+	}
+
+	// 4.传送失败时告知客户端原因
+	if err != nil {
+		fmt.Printf("Player pid=%d teleport failed: %s\n", player.Pid, err)
+		player.SendTeleportResult(err)
+	}
+}
@@ -4,36 +4,116 @@ import (
 	"fmt"
 	"szinx/core"
 	"szinx/pb"
+	"time"
 
 	"github.com/YungMonk/zinx/ziface"
 	"github.com/YungMonk/zinx/znet"
 	"github.com/golang/protobuf/proto"
 )
 
-// WorldChatAPI 世界聊天的路由业务
+// WorldChatAPI 聊天的路由业务，根据 MsgID 区分聊天范围
+// MsgID=2 场景聊天，7 附近聊天，8 私聊，9 世界聊天，10 频道聊天，11 加入频道，12 离开频道，13 查询聊天记录
 type WorldChatAPI struct {
 	znet.BaseRouter
+
+	// 路由处理的玩家所在的世界
+	World *core.WorldManager
 }
 
 // Handle 处理 Connection 主业务的钩子方法 Hook
 func (wc *WorldChatAPI) Handle(request ziface.IRequest) {
-	// 1.解析客户端传递的proto协议
-	protoMsg := &pb.Talk{}
-	if err := proto.Unmarshal(request.GetData(), protoMsg); err != nil {
-		fmt.Println("Talk proto unmarshal err:", err)
+	// 1.当前的聊天数据是那个玩家发送的
+	player := loginPlayer(wc.World, request)
+	if player == nil {
 		return
 	}
 
-	// 2.当前的聊天数据是那个玩家发送的
-	pid, err := request.GetConnection().GetProperty("pid")
-	if err != nil {
-		fmt.Printf("pid not found")
-		return
+	// 2.解析客户端传递的proto协议，并按聊天范围发送给其它的玩家，失败时告知发送者原因
+	if remain, err := wc.route(player, request.GetMsgID(), request.GetData()); err != nil {
+		fmt.Printf("Player pid=%d chat msgID=%d failed: %s\n", player.Pid, request.GetMsgID(), err)
+		player.SendChatResult(remain, err)
 	}
+}
+
+// route 根据 MsgID 解析聊天消息并交给对应的聊天范围处理
+// 协议解析失败时只记录日志，返回的错误会告知发送者
+func (wc *WorldChatAPI) route(player *core.Player, msgID uint32, data []byte) (time.Duration, error) {
+	chat := &pb.Chat{
+		Pid: player.Pid,
+	}
+
+	switch msgID {
+	case 2:
+		protoMsg := &pb.Talk{}
+		if err := proto.Unmarshal(data, protoMsg); err != nil {
+			fmt.Println("Talk proto unmarshal err:", err)
+			return 0, nil
+		}
+		chat.Scope = core.ChatScopeScene
+		chat.Content = protoMsg.Content
+
+	case 7:
+		protoMsg := &pb.Say{}
+		if err := proto.Unmarshal(data, protoMsg); err != nil {
+			fmt.Println("Say proto unmarshal err:", err)
+			return 0, nil
+		}
+		chat.Scope = core.ChatScopeSay
+		chat.Content = protoMsg.Content
 
-	// 3.根据pid得到player对象
-	player := core.WorldMgrObj.GetPlayerByPid(pid.(int32))
+	case 8:
+		protoMsg := &pb.Whisper{}
+		if err := proto.Unmarshal(data, protoMsg); err != nil {
+			fmt.Println("Whisper proto unmarshal err:", err)
+			return 0, nil
+		}
+		chat.Scope = core.ChatScopeWhisper
+		chat.Target = protoMsg.Target
+		chat.Content = protoMsg.Content
+
+	case 9:
+		protoMsg := &pb.WorldTalk{}
+		if err := proto.Unmarshal(data, protoMsg); err != nil {
+			fmt.Println("WorldTalk proto unmarshal err:", err)
+			return 0, nil
+		}
+		chat.Scope = core.ChatScopeWorld
+		chat.Content = protoMsg.Content
+
+	case 10:
+		protoMsg := &pb.ChannelTalk{}
+		if err := proto.Unmarshal(data, protoMsg); err != nil {
+			fmt.Println("ChannelTalk proto unmarshal err:", err)
+			return 0, nil
+		}
+		chat.Scope = core.ChatScopeChannel
+		chat.Channel = protoMsg.Channel
+		chat.Content = protoMsg.Content
+
+	case 11, 12:
+		protoMsg := &pb.ChannelOp{}
+		if err := proto.Unmarshal(data, protoMsg); err != nil {
+			fmt.Println("ChannelOp proto unmarshal err:", err)
+			return 0, nil
+		}
+		if msgID == 11 {
+			return 0, player.JoinChannel(protoMsg.Channel)
+		}
+		return 0, player.LeaveChannel(protoMsg.Channel)
+
+	case 13:
+		protoMsg := &pb.ChatHistoryQuery{}
+		if err := proto.Unmarshal(data, protoMsg); err != nil {
+			fmt.Println("ChatHistoryQuery proto unmarshal err:", err)
+			return 0, nil
+		}
+		return 0, player.SendChatHistory(protoMsg.Scope, protoMsg.Channel, protoMsg.Before, int(protoMsg.Limit))
+
+	default:
+		fmt.Println("unknown chat msgID:", msgID)
+		return 0, nil
+	}
 
-	// 4.将这个消息广播给其它的玩家
-	player.Talk(protoMsg.Content)
+	// 校验禁言及发送频率之后再发送
+	return player.Chat(chat, time.Now())
 }
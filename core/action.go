@@ -0,0 +1,56 @@
+package core
+
+import (
+	"time"
+
+	"szinx/pb"
+)
+
+// DoAction 玩家做出一个动作，校验动作是否存在以及是否在冷却中，然后广播给视野内的玩家（包括自己）
+// 校验失败时返回错误以及冷却剩余的时间
+func (p *Player) DoAction(actionID int32, now time.Time) (time.Duration, error) {
+	// 1.校验动作是否存在
+	action, ok := p.world.Actions[actionID]
+	if !ok {
+		return 0, ErrActionNotFound
+	}
+
+	// 2.校验动作的冷却时间，每个动作单独冷却
+	p.actionLock.Lock()
+	if ready, ok := p.actionReady[actionID]; ok && now.Before(ready) {
+		p.actionLock.Unlock()
+		return ready.Sub(now), ErrActionCooldown
+	}
+	if p.actionReady == nil {
+		p.actionReady = make(map[int32]time.Time)
+	}
+	p.actionReady[actionID] = now.Add(time.Duration(action.Cooldown) * time.Millisecond)
+	p.actionLock.Unlock()
+
+	// 3.组建 MsgID:200 的 proto 数据，广播给视野内的玩家
+	protoMsg := &pb.BroadCast{
+		Pid: p.Pid,
+		Tp:  3,
+		Data: &pb.BroadCast_ActionData{
+			ActionData: actionID,
+		},
+	}
+	for _, player := range p.GetSurroundingPlayers() {
+		player.SendMsg(200, protoMsg)
+	}
+
+	return 0, nil
+}
+
+// SendActionResult 将动作被拒绝的原因发送给客户端
+func (p *Player) SendActionResult(actionID int32, remain time.Duration, err error) {
+	// 组建 MsgID:208 的 proto 数据
+	protoMsg := &pb.ActionResult{
+		ActionID: actionID,
+		Code:     ActionCode(err),
+		Msg:      err.Error(),
+		Remain:   int32(remain / time.Millisecond),
+	}
+
+	p.SendMsg(208, protoMsg)
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"szinx/pb"
+)
+
+func TestPlayerDoAction(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	scene := world.DefaultScene()
+	conn1, conn2 := &fakeConn{}, &fakeConn{}
+	p1, p2 := newTestPlayer(t, world, conn1, scene), newTestPlayer(t, world, conn2, scene)
+	world.AddPlayer(p1)
+	world.AddPlayer(p2)
+	now := time.Now()
+
+	// 不存在的动作
+	if _, err := p1.DoAction(99, now); err != ErrActionNotFound {
+		t.Errorf("err=%v, want ErrActionNotFound", err)
+	}
+
+	// 动作广播给视野内的玩家（包括自己）
+	if _, err := p1.DoAction(2, now); err != nil {
+		t.Fatal(err)
+	}
+	msg := &pb.BroadCast{}
+	if !conn2.last(200, msg) || msg.Tp != 3 || msg.GetActionData() != 2 || msg.Pid != p1.Pid {
+		t.Errorf("msg=%v", msg)
+	}
+	if conn1.count(200) != 1 {
+		t.Errorf("self count=%d, want 1", conn1.count(200))
+	}
+
+	// 冷却中的动作被拒绝，其它动作不受影响
+	if remain, err := p1.DoAction(2, now.Add(200*time.Millisecond)); err != ErrActionCooldown || remain != 300*time.Millisecond {
+		t.Errorf("remain=%v, err=%v, want ErrActionCooldown", remain, err)
+	}
+	if _, err := p1.DoAction(1, now.Add(200*time.Millisecond)); err != nil {
+		t.Errorf("err=%v, want nil", err)
+	}
+
+	// 冷却结束之后可以再次做出动作
+	if _, err := p1.DoAction(2, now.Add(500*time.Millisecond)); err != nil {
+		t.Errorf("err=%v, want nil", err)
+	}
+}
@@ -2,17 +2,14 @@ package core
 
 import (
 	"fmt"
+	"sync"
 )
 
-// 定义一些 AOI 的边界值
-const (
-	AOIMINX int = 85
-	AOIMAXX int = 410
-	AOICNTX int = 10
-	AOIMINY int = 75
-	AOIMAXY int = 400
-	AOICNTY int = 20
-)
+// aoiPos 玩家在 AOI 平面中的坐标
+type aoiPos struct {
+	X float32
+	Y float32
+}
 
 // AOIManager AOI 区域管理模块
 type AOIManager struct {
@@ -30,6 +27,12 @@ type AOIManager struct {
 	CntsY int
 	// 当前区域中有那些格子 map-key=格子的ID，value=格子对象
 	Grids map[int]*Grid
+	// 视野半径，大于 0 时按半径查询视野，否则查询九宫格
+	Radius float32
+	// 当前区域中玩家的坐标 map-key=玩家ID，value=坐标
+	positions map[int]aoiPos
+	// 保护 positions 的锁
+	posLock sync.RWMutex
 }
 
 // NewAOIManager 初始化 AOI 区域管理模块的方法
@@ -42,6 +45,8 @@ func NewAOIManager(minx, maxx, cntsx, miny, maxy, cntsy int) *AOIManager {
 		CntsX: cntsx,
 		CntsY: cntsy,
 		Grids: make(map[int]*Grid),
+
+		positions: make(map[int]aoiPos),
 	}
 
 	// 给 AOI 初始化区域的格子的所有的格子进行编号和初始化
@@ -122,14 +127,75 @@ func (am *AOIManager) GetSurroundGridsByGid(gid int) (grids []*Grid) {
 
 // GetGidByPos 通过 x，y来获取格子的gid
 func (am *AOIManager) GetGidByPos(x, y float32) int {
-	idx := (int(x) - am.MinX) / am.CntsX
-	idy := (int(y) - am.MinY) / am.CntsY
+	idx := (int(x) - am.MinX) / am.gridWith()
+	idy := (int(y) - am.MinY) / am.gridLength()
 
 	return idy*am.CntsX + idx
 }
 
-// GetPidsByPos 通过横纵坐标获取周边九宫格内的所有 playerIDs
+// GetPidsByGrids 获取一组格子中的所有 playerIDs
+func (am *AOIManager) GetPidsByGrids(grids []*Grid) (playerIDs []int) {
+	for _, grid := range grids {
+		playerIDs = append(playerIDs, grid.GetPlayerIDs()...)
+	}
+
+	return playerIDs
+}
+
+// DiffSurroundGrids 比较两个格子的九宫格，得到离开视野的格子和进入视野的格子
+func (am *AOIManager) DiffSurroundGrids(oldGid, newGid int) (leaveGrids, enterGrids []*Grid) {
+	oldGrids := am.GetSurroundGridsByGid(oldGid)
+	newGrids := am.GetSurroundGridsByGid(newGid)
+
+	// 记录旧九宫格与新九宫格中的格子
+	oldSet := make(map[int]bool, len(oldGrids))
+	for _, grid := range oldGrids {
+		oldSet[grid.GID] = true
+	}
+	newSet := make(map[int]bool, len(newGrids))
+	for _, grid := range newGrids {
+		newSet[grid.GID] = true
+	}
+
+	// 在旧九宫格中，但不在新九宫格中的格子离开视野
+	for _, grid := range oldGrids {
+		if !newSet[grid.GID] {
+			leaveGrids = append(leaveGrids, grid)
+		}
+	}
+
+	// 在新九宫格中，但不在旧九宫格中的格子进入视野
+	for _, grid := range newGrids {
+		if !oldSet[grid.GID] {
+			enterGrids = append(enterGrids, grid)
+		}
+	}
+
+	return leaveGrids, enterGrids
+}
+
+// SetRadius 设置视野半径，radius 大于 0 时切换为半径视野，否则使用九宫格视野
+func (am *AOIManager) SetRadius(radius float32) {
+	am.Radius = radius
+}
+
+// IsRadiusMode 当前是否使用半径视野
+func (am *AOIManager) IsRadiusMode() bool {
+	return am.Radius > 0
+}
+
+// GetPidsByPos 通过横纵坐标获取视野内的所有 playerIDs
+// 半径模式下返回半径内的玩家，否则返回周边九宫格内的玩家
 func (am *AOIManager) GetPidsByPos(x, y float32) (playerIDs []int) {
+	if am.IsRadiusMode() {
+		return am.GetPidsByRadius(x, y, am.Radius)
+	}
+
+	return am.GetPidsBySurroundGrids(x, y)
+}
+
+// GetPidsBySurroundGrids 通过横纵坐标获取周边九宫格内的所有 playerIDs
+func (am *AOIManager) GetPidsBySurroundGrids(x, y float32) (playerIDs []int) {
 	// 得到当前坐标的gid
 	gid := am.GetGidByPos(x, y)
 
@@ -145,6 +211,46 @@ func (am *AOIManager) GetPidsByPos(x, y float32) (playerIDs []int) {
 	return playerIDs
 }
 
+// GetGridsByRect 获取与矩形区域相交的所有格子
+func (am *AOIManager) GetGridsByRect(minx, maxx, miny, maxy float32) (grids []*Grid) {
+	// 计算矩形区域覆盖的格子编号范围，并限制在 AOI 区域内
+	idxMin := clampInt((int(minx)-am.MinX)/am.gridWith(), 0, am.CntsX-1)
+	idxMax := clampInt((int(maxx)-am.MinX)/am.gridWith(), 0, am.CntsX-1)
+	idyMin := clampInt((int(miny)-am.MinY)/am.gridLength(), 0, am.CntsY-1)
+	idyMax := clampInt((int(maxy)-am.MinY)/am.gridLength(), 0, am.CntsY-1)
+
+	for y := idyMin; y <= idyMax; y++ {
+		for x := idxMin; x <= idxMax; x++ {
+			grids = append(grids, am.Grids[y*am.CntsX+x])
+		}
+	}
+
+	return grids
+}
+
+// GetPidsByRadius 获取以 (x, y) 为圆心，radius 为半径范围内的所有 playerIDs
+// 先用格子筛选出外接正方形内的玩家，再按欧氏距离精确过滤
+func (am *AOIManager) GetPidsByRadius(x, y, radius float32) (playerIDs []int) {
+	grids := am.GetGridsByRect(x-radius, x+radius, y-radius, y+radius)
+
+	am.posLock.RLock()
+	defer am.posLock.RUnlock()
+
+	for _, pid := range am.GetPidsByGrids(grids) {
+		pos, ok := am.positions[pid]
+		if !ok {
+			continue
+		}
+
+		dx, dy := pos.X-x, pos.Y-y
+		if dx*dx+dy*dy <= radius*radius {
+			playerIDs = append(playerIDs, pid)
+		}
+	}
+
+	return playerIDs
+}
+
 // AddPidToGrid 给格子中添加一个 playerid
 func (am *AOIManager) AddPidToGrid(pid, gid int) {
 	am.Grids[gid].Add(pid)
@@ -164,12 +270,121 @@ func (am *AOIManager) GetPidsByGid(gid int) (playerids []int) {
 func (am *AOIManager) AddPidToGridByPos(pid int, x, y float32) {
 	gid := am.GetGidByPos(x, y)
 	am.Grids[gid].Add(pid)
+
+	am.posLock.Lock()
+	am.positions[pid] = aoiPos{X: x, Y: y}
+	am.posLock.Unlock()
 }
 
 // RemovePidFromGridByPos 通过坐标移除格子中的一个 playerid
 func (am *AOIManager) RemovePidFromGridByPos(pid int, x, y float32) {
 	gid := am.GetGidByPos(x, y)
 	am.Grids[gid].Remove(pid)
+
+	am.posLock.Lock()
+	delete(am.positions, pid)
+	am.posLock.Unlock()
+}
+
+// MovePidByPos 玩家从旧坐标移动到新坐标，跨越格子时更新格子中的 playerid
+func (am *AOIManager) MovePidByPos(pid int, oldx, oldy, x, y float32) (oldGid, newGid int) {
+	oldGid = am.GetGidByPos(oldx, oldy)
+	newGid = am.GetGidByPos(x, y)
+	if oldGid != newGid {
+		am.Grids[oldGid].Remove(pid)
+		am.Grids[newGid].Add(pid)
+	}
+
+	am.posLock.Lock()
+	am.positions[pid] = aoiPos{X: x, Y: y}
+	am.posLock.Unlock()
+
+	return oldGid, newGid
+}
+
+// Add 实现 IAOI 接口，将玩家添加到坐标所在的格子中
+func (am *AOIManager) Add(pid int, x, y float32) {
+	am.AddPidToGridByPos(pid, x, y)
+}
+
+// Remove 实现 IAOI 接口，将玩家从所在的格子中移除
+func (am *AOIManager) Remove(pid int) {
+	am.posLock.RLock()
+	pos, ok := am.positions[pid]
+	am.posLock.RUnlock()
+	if !ok {
+		return
+	}
+
+	am.RemovePidFromGridByPos(pid, pos.X, pos.Y)
+}
+
+// Move 实现 IAOI 接口，玩家移动到新坐标，返回离开视野和进入视野的 playerIDs
+func (am *AOIManager) Move(pid int, x, y float32) (leavePids, enterPids []int) {
+	am.posLock.RLock()
+	oldPos, ok := am.positions[pid]
+	am.posLock.RUnlock()
+	if !ok {
+		am.Add(pid, x, y)
+		return nil, am.GetPidsByPos(x, y)
+	}
+
+	// 半径视野，比较移动前后视野内的玩家
+	if am.IsRadiusMode() {
+		oldPids := am.GetPidsByPos(oldPos.X, oldPos.Y)
+		am.MovePidByPos(pid, oldPos.X, oldPos.Y, x, y)
+		return DiffPids(oldPids, am.GetPidsByPos(x, y))
+	}
+
+	// 九宫格视野，只有跨越格子时才比较移动前后的九宫格
+	oldGid, newGid := am.MovePidByPos(pid, oldPos.X, oldPos.Y, x, y)
+	if oldGid == newGid {
+		return nil, nil
+	}
+	leaveGrids, enterGrids := am.DiffSurroundGrids(oldGid, newGid)
+
+	return am.GetPidsByGrids(leaveGrids), am.GetPidsByGrids(enterGrids)
+}
+
+// GetBounds 实现 IAOI 接口，获取区域的边界坐标
+func (am *AOIManager) GetBounds() (minX, maxX, minY, maxY int) {
+	return am.MinX, am.MaxX, am.MinY, am.MaxY
+}
+
+// DiffPids 比较移动前后视野内的 playerIDs，得到离开视野和进入视野的 playerIDs
+func DiffPids(oldPids, newPids []int) (leavePids, enterPids []int) {
+	oldSet := make(map[int]bool, len(oldPids))
+	for _, pid := range oldPids {
+		oldSet[pid] = true
+	}
+	newSet := make(map[int]bool, len(newPids))
+	for _, pid := range newPids {
+		newSet[pid] = true
+	}
+
+	for _, pid := range oldPids {
+		if !newSet[pid] {
+			leavePids = append(leavePids, pid)
+		}
+	}
+	for _, pid := range newPids {
+		if !oldSet[pid] {
+			enterPids = append(enterPids, pid)
+		}
+	}
+
+	return leavePids, enterPids
+}
+
+// clampInt 将 v 限制在 [min, max] 范围内
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
 }
 
 // 打印格子信息
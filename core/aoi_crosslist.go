@@ -0,0 +1,260 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// 十字链表的两条坐标轴
+const (
+	axisX = 0 // 平面 x 轴
+	axisY = 1 // 平面 y 轴（玩家的 Z 坐标）
+)
+
+// crossNode 十字链表中的节点，同时挂在 x 轴和 y 轴两条有序链表上
+type crossNode struct {
+	// 玩家ID
+	pid int
+	// 节点在两条坐标轴上的坐标
+	pos [2]float32
+	// 节点在两条坐标轴链表中的前驱节点
+	prev [2]*crossNode
+	// 节点在两条坐标轴链表中的后继节点
+	next [2]*crossNode
+}
+
+// CrossListAOI 十字链表 AOI 管理模块
+// 玩家分别按 x、y 坐标挂在两条有序双向链表上，不需要预先划分格子
+type CrossListAOI struct {
+	// 区域的左边边界坐标
+	MinX int
+	// 区域的右边边界坐标
+	MaxX int
+	// 区域的下边边界坐标
+	MinY int
+	// 区域的上边边界坐标
+	MaxY int
+	// 视野半径
+	Radius float32
+	// 两条坐标轴链表的头节点
+	heads [2]*crossNode
+	// 当前区域中所有的节点 map-key=玩家ID，value=节点
+	nodes map[int]*crossNode
+	// 保护链表和节点集合的锁
+	lock sync.RWMutex
+}
+
+// NewCrossListAOI 初始化十字链表 AOI 管理模块的方法
+func NewCrossListAOI(minx, maxx, miny, maxy int, radius float32) *CrossListAOI {
+	return &CrossListAOI{
+		MinX:   minx,
+		MaxX:   maxx,
+		MinY:   miny,
+		MaxY:   maxy,
+		Radius: radius,
+		nodes:  make(map[int]*crossNode),
+	}
+}
+
+// Add 实现 IAOI 接口，将玩家按坐标插入两条有序链表
+func (cl *CrossListAOI) Add(pid int, x, y float32) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	if _, ok := cl.nodes[pid]; ok {
+		return
+	}
+
+	node := &crossNode{pid: pid, pos: [2]float32{x, y}}
+	cl.insert(node, axisX)
+	cl.insert(node, axisY)
+	cl.nodes[pid] = node
+}
+
+// Remove 实现 IAOI 接口，将玩家从两条链表中移除
+func (cl *CrossListAOI) Remove(pid int) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	node, ok := cl.nodes[pid]
+	if !ok {
+		return
+	}
+
+	cl.unlink(node, axisX)
+	cl.unlink(node, axisY)
+	delete(cl.nodes, pid)
+}
+
+// Move 实现 IAOI 接口，玩家移动到新坐标，返回离开视野和进入视野的 playerIDs
+func (cl *CrossListAOI) Move(pid int, x, y float32) (leavePids, enterPids []int) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	node, ok := cl.nodes[pid]
+	if !ok {
+		node = &crossNode{pid: pid, pos: [2]float32{x, y}}
+		cl.insert(node, axisX)
+		cl.insert(node, axisY)
+		cl.nodes[pid] = node
+		return nil, cl.getPidsAround(node)
+	}
+
+	oldPids := cl.getPidsAround(node)
+
+	// 玩家一般只移动很短的距离，从当前位置向前后调整即可保持链表有序
+	node.pos = [2]float32{x, y}
+	cl.reorder(node, axisX)
+	cl.reorder(node, axisY)
+
+	return DiffPids(oldPids, cl.getPidsAround(node))
+}
+
+// GetPidsByPos 实现 IAOI 接口，获取坐标视野半径内的所有 playerIDs
+func (cl *CrossListAOI) GetPidsByPos(x, y float32) (playerIDs []int) {
+	cl.lock.RLock()
+	defer cl.lock.RUnlock()
+
+	// 沿 x 轴链表找到进入 [x-Radius, x+Radius] 区间的第一个节点
+	node := cl.heads[axisX]
+	for node != nil && node.pos[axisX] < x-cl.Radius {
+		node = node.next[axisX]
+	}
+
+	// 遍历区间内的节点，按欧氏距离过滤
+	for ; node != nil && node.pos[axisX] <= x+cl.Radius; node = node.next[axisX] {
+		if cl.inRadius(node, x, y) {
+			playerIDs = append(playerIDs, node.pid)
+		}
+	}
+
+	return playerIDs
+}
+
+// GetBounds 实现 IAOI 接口，获取区域的边界坐标
+func (cl *CrossListAOI) GetBounds() (minX, maxX, minY, maxY int) {
+	return cl.MinX, cl.MaxX, cl.MinY, cl.MaxY
+}
+
+// getPidsAround 获取节点视野半径内的所有 playerIDs（包括自己）
+// 同时沿 x 轴和 y 轴链表向两边展开，哪条轴先遍历完区间就用哪条轴的结果，
+// 这样在玩家沿某一条轴密集分布时只需要遍历另一条轴
+func (cl *CrossListAOI) getPidsAround(node *crossNode) (playerIDs []int) {
+	var candidates [2][]*crossNode
+	var cursors [2][2]*crossNode
+	for axis := axisX; axis <= axisY; axis++ {
+		cursors[axis] = [2]*crossNode{node.prev[axis], node.next[axis]}
+	}
+
+	for axis := axisX; ; axis = 1 - axis {
+		prev, next := cursors[axis][0], cursors[axis][1]
+		if prev != nil && node.pos[axis]-prev.pos[axis] > cl.Radius {
+			prev = nil
+		}
+		if next != nil && next.pos[axis]-node.pos[axis] > cl.Radius {
+			next = nil
+		}
+
+		// 当前轴已经遍历完区间
+		if prev == nil && next == nil {
+			playerIDs = append(playerIDs, node.pid)
+			for _, candidate := range candidates[axis] {
+				if cl.inRadius(candidate, node.pos[axisX], node.pos[axisY]) {
+					playerIDs = append(playerIDs, candidate.pid)
+				}
+			}
+			return playerIDs
+		}
+
+		if prev != nil {
+			candidates[axis] = append(candidates[axis], prev)
+			prev = prev.prev[axis]
+		}
+		if next != nil {
+			candidates[axis] = append(candidates[axis], next)
+			next = next.next[axis]
+		}
+		cursors[axis] = [2]*crossNode{prev, next}
+	}
+}
+
+// inRadius 判断节点是否在坐标的视野半径内
+func (cl *CrossListAOI) inRadius(node *crossNode, x, y float32) bool {
+	dx, dy := node.pos[axisX]-x, node.pos[axisY]-y
+	return dx*dx+dy*dy <= cl.Radius*cl.Radius
+}
+
+// insert 从链表头开始查找，将节点插入到坐标轴链表中的有序位置
+func (cl *CrossListAOI) insert(node *crossNode, axis int) {
+	var prev *crossNode
+	next := cl.heads[axis]
+	for next != nil && next.pos[axis] < node.pos[axis] {
+		prev, next = next, next.next[axis]
+	}
+
+	cl.linkBetween(node, prev, next, axis)
+}
+
+// reorder 节点坐标变化后，从当前位置向前或向后移动到有序位置
+func (cl *CrossListAOI) reorder(node *crossNode, axis int) {
+	prev, next := node.prev[axis], node.next[axis]
+	if (prev == nil || prev.pos[axis] <= node.pos[axis]) &&
+		(next == nil || next.pos[axis] >= node.pos[axis]) {
+		return
+	}
+
+	cl.unlink(node, axis)
+
+	// 向后查找
+	for next != nil && next.pos[axis] < node.pos[axis] {
+		prev, next = next, next.next[axis]
+	}
+	// 向前查找
+	for prev != nil && prev.pos[axis] > node.pos[axis] {
+		prev, next = prev.prev[axis], prev
+	}
+
+	cl.linkBetween(node, prev, next, axis)
+}
+
+// linkBetween 将节点链接到 prev 和 next 之间
+func (cl *CrossListAOI) linkBetween(node, prev, next *crossNode, axis int) {
+	node.prev[axis], node.next[axis] = prev, next
+	if prev != nil {
+		prev.next[axis] = node
+	} else {
+		cl.heads[axis] = node
+	}
+	if next != nil {
+		next.prev[axis] = node
+	}
+}
+
+// unlink 将节点从坐标轴链表中摘除
+func (cl *CrossListAOI) unlink(node *crossNode, axis int) {
+	prev, next := node.prev[axis], node.next[axis]
+	if prev != nil {
+		prev.next[axis] = next
+	} else {
+		cl.heads[axis] = next
+	}
+	if next != nil {
+		next.prev[axis] = prev
+	}
+	node.prev[axis], node.next[axis] = nil, nil
+}
+
+// 打印十字链表信息
+func (cl *CrossListAOI) String() string {
+	str := fmt.Sprintf(
+		"CrossListAOI:\nMinX:%d, MaxX:%d, MinY:%d, MaxY:%d, Radius:%f\n",
+		cl.MinX, cl.MaxX, cl.MinY, cl.MaxY, cl.Radius,
+	)
+
+	// 按 x 轴顺序打印所有节点
+	for node := cl.heads[axisX]; node != nil; node = node.next[axisX] {
+		str += fmt.Sprintf("pid:%d, x:%f, y:%f\n", node.pid, node.pos[axisX], node.pos[axisY])
+	}
+
+	return str
+}
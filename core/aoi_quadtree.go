@@ -0,0 +1,312 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// 四叉树的默认参数
+const (
+	QuadTreeCapacity = 8  // 叶子节点最多容纳的玩家数量，超过之后分裂
+	QuadTreeMaxDepth = 10 // 四叉树的最大深度
+)
+
+// quadNode 四叉树的节点，叶子节点保存玩家，非叶子节点保存四个子节点
+type quadNode struct {
+	// 节点区域的边界坐标
+	minX, maxX, minY, maxY float32
+	// 节点的深度，根节点为 0
+	depth int
+	// 叶子节点中的玩家 map-key=玩家ID，value=坐标
+	items map[int]aoiPos
+	// 四个子节点，叶子节点为 nil
+	children *[4]*quadNode
+}
+
+// newQuadNode 创建一个四叉树的叶子节点
+func newQuadNode(minx, maxx, miny, maxy float32, depth int) *quadNode {
+	return &quadNode{
+		minX:  minx,
+		maxX:  maxx,
+		minY:  miny,
+		maxY:  maxy,
+		depth: depth,
+		items: make(map[int]aoiPos),
+	}
+}
+
+// isLeaf 是否为叶子节点
+func (qn *quadNode) isLeaf() bool {
+	return qn.children == nil
+}
+
+// childIndex 坐标所在的子节点编号，0-左下，1-右下，2-左上，3-右上
+func (qn *quadNode) childIndex(pos aoiPos) int {
+	idx := 0
+	if pos.X >= (qn.minX+qn.maxX)/2 {
+		idx |= 1
+	}
+	if pos.Y >= (qn.minY+qn.maxY)/2 {
+		idx |= 2
+	}
+	return idx
+}
+
+// intersects 节点区域是否与矩形相交
+func (qn *quadNode) intersects(minx, maxx, miny, maxy float32) bool {
+	return qn.minX <= maxx && qn.maxX >= minx && qn.minY <= maxy && qn.maxY >= miny
+}
+
+// QuadTreeAOI 四叉树 AOI 管理模块
+// 只在玩家聚集的区域分裂节点，适合地图很大而玩家稀疏的场景
+type QuadTreeAOI struct {
+	// 区域的左边边界坐标
+	MinX int
+	// 区域的右边边界坐标
+	MaxX int
+	// 区域的下边边界坐标
+	MinY int
+	// 区域的上边边界坐标
+	MaxY int
+	// 视野半径
+	Radius float32
+	// 叶子节点最多容纳的玩家数量
+	Capacity int
+	// 四叉树的最大深度
+	MaxDepth int
+	// 四叉树的根节点
+	root *quadNode
+	// 当前区域中玩家的坐标 map-key=玩家ID，value=坐标
+	positions map[int]aoiPos
+	// 保护四叉树的锁
+	lock sync.RWMutex
+}
+
+// NewQuadTreeAOI 初始化四叉树 AOI 管理模块的方法
+func NewQuadTreeAOI(minx, maxx, miny, maxy int, radius float32) *QuadTreeAOI {
+	return &QuadTreeAOI{
+		MinX:      minx,
+		MaxX:      maxx,
+		MinY:      miny,
+		MaxY:      maxy,
+		Radius:    radius,
+		Capacity:  QuadTreeCapacity,
+		MaxDepth:  QuadTreeMaxDepth,
+		root:      newQuadNode(float32(minx), float32(maxx), float32(miny), float32(maxy), 0),
+		positions: make(map[int]aoiPos),
+	}
+}
+
+// Add 实现 IAOI 接口，将玩家插入四叉树
+func (qt *QuadTreeAOI) Add(pid int, x, y float32) {
+	qt.lock.Lock()
+	defer qt.lock.Unlock()
+
+	if _, ok := qt.positions[pid]; ok {
+		return
+	}
+
+	pos := aoiPos{X: x, Y: y}
+	qt.insert(qt.root, pid, pos)
+	qt.positions[pid] = pos
+}
+
+// Remove 实现 IAOI 接口，将玩家从四叉树中移除
+func (qt *QuadTreeAOI) Remove(pid int) {
+	qt.lock.Lock()
+	defer qt.lock.Unlock()
+
+	pos, ok := qt.positions[pid]
+	if !ok {
+		return
+	}
+
+	qt.remove(qt.root, pid, pos)
+	delete(qt.positions, pid)
+}
+
+// Move 实现 IAOI 接口，玩家移动到新坐标，返回离开视野和进入视野的 playerIDs
+func (qt *QuadTreeAOI) Move(pid int, x, y float32) (leavePids, enterPids []int) {
+	qt.lock.Lock()
+	defer qt.lock.Unlock()
+
+	pos := aoiPos{X: x, Y: y}
+	oldPos, ok := qt.positions[pid]
+	if !ok {
+		qt.insert(qt.root, pid, pos)
+		qt.positions[pid] = pos
+		return nil, qt.getPidsByRadius(x, y, qt.Radius)
+	}
+
+	oldPids := qt.getPidsByRadius(oldPos.X, oldPos.Y, qt.Radius)
+
+	// 仍在同一个叶子节点中时只更新坐标，否则重新插入
+	if leaf := qt.leafOf(oldPos); leaf == qt.leafOf(pos) {
+		leaf.items[pid] = pos
+	} else {
+		qt.remove(qt.root, pid, oldPos)
+		qt.insert(qt.root, pid, pos)
+	}
+	qt.positions[pid] = pos
+
+	return DiffPids(oldPids, qt.getPidsByRadius(x, y, qt.Radius))
+}
+
+// GetPidsByPos 实现 IAOI 接口，获取坐标视野半径内的所有 playerIDs
+func (qt *QuadTreeAOI) GetPidsByPos(x, y float32) []int {
+	return qt.GetPidsByRadius(x, y, qt.Radius)
+}
+
+// GetBounds 实现 IAOI 接口，获取区域的边界坐标
+func (qt *QuadTreeAOI) GetBounds() (minX, maxX, minY, maxY int) {
+	return qt.MinX, qt.MaxX, qt.MinY, qt.MaxY
+}
+
+// GetPidsByRect 获取矩形区域内的所有 playerIDs
+func (qt *QuadTreeAOI) GetPidsByRect(minx, maxx, miny, maxy float32) (playerIDs []int) {
+	qt.lock.RLock()
+	defer qt.lock.RUnlock()
+
+	qt.queryRect(qt.root, minx, maxx, miny, maxy, func(pid int, pos aoiPos) {
+		playerIDs = append(playerIDs, pid)
+	})
+
+	return playerIDs
+}
+
+// GetPidsByRadius 获取以 (x, y) 为圆心，radius 为半径范围内的所有 playerIDs
+func (qt *QuadTreeAOI) GetPidsByRadius(x, y, radius float32) []int {
+	qt.lock.RLock()
+	defer qt.lock.RUnlock()
+
+	return qt.getPidsByRadius(x, y, radius)
+}
+
+// getPidsByRadius 先按外接正方形查询，再按欧氏距离过滤，调用方需要持有锁
+func (qt *QuadTreeAOI) getPidsByRadius(x, y, radius float32) (playerIDs []int) {
+	qt.queryRect(qt.root, x-radius, x+radius, y-radius, y+radius, func(pid int, pos aoiPos) {
+		dx, dy := pos.X-x, pos.Y-y
+		if dx*dx+dy*dy <= radius*radius {
+			playerIDs = append(playerIDs, pid)
+		}
+	})
+
+	return playerIDs
+}
+
+// queryRect 遍历与矩形相交的叶子节点，对矩形内的玩家调用 fn
+func (qt *QuadTreeAOI) queryRect(node *quadNode, minx, maxx, miny, maxy float32, fn func(pid int, pos aoiPos)) {
+	if !node.intersects(minx, maxx, miny, maxy) {
+		return
+	}
+
+	if !node.isLeaf() {
+		for _, child := range node.children {
+			qt.queryRect(child, minx, maxx, miny, maxy, fn)
+		}
+		return
+	}
+
+	for pid, pos := range node.items {
+		if pos.X >= minx && pos.X <= maxx && pos.Y >= miny && pos.Y <= maxy {
+			fn(pid, pos)
+		}
+	}
+}
+
+// leafOf 坐标所在的叶子节点
+func (qt *QuadTreeAOI) leafOf(pos aoiPos) *quadNode {
+	node := qt.root
+	for !node.isLeaf() {
+		node = node.children[node.childIndex(pos)]
+	}
+	return node
+}
+
+// insert 将玩家插入到节点中，叶子节点超过容量时分裂
+func (qt *QuadTreeAOI) insert(node *quadNode, pid int, pos aoiPos) {
+	for !node.isLeaf() {
+		node = node.children[node.childIndex(pos)]
+	}
+
+	node.items[pid] = pos
+	if len(node.items) > qt.Capacity && node.depth < qt.MaxDepth {
+		qt.split(node)
+	}
+}
+
+// split 将叶子节点分裂为四个子节点，并把玩家分配到子节点中
+func (qt *QuadTreeAOI) split(node *quadNode) {
+	midX, midY := (node.minX+node.maxX)/2, (node.minY+node.maxY)/2
+	depth := node.depth + 1
+	node.children = &[4]*quadNode{
+		newQuadNode(node.minX, midX, node.minY, midY, depth),
+		newQuadNode(midX, node.maxX, node.minY, midY, depth),
+		newQuadNode(node.minX, midX, midY, node.maxY, depth),
+		newQuadNode(midX, node.maxX, midY, node.maxY, depth),
+	}
+
+	items := node.items
+	node.items = nil
+	for pid, pos := range items {
+		qt.insert(node.children[node.childIndex(pos)], pid, pos)
+	}
+}
+
+// remove 将玩家从节点中移除，子节点中的玩家足够少时合并为叶子节点
+func (qt *QuadTreeAOI) remove(node *quadNode, pid int, pos aoiPos) {
+	if node.isLeaf() {
+		delete(node.items, pid)
+		return
+	}
+
+	qt.remove(node.children[node.childIndex(pos)], pid, pos)
+
+	// 子节点全部是叶子，并且玩家数量不超过容量时合并
+	count := 0
+	for _, child := range node.children {
+		if !child.isLeaf() {
+			return
+		}
+		count += len(child.items)
+	}
+	if count > qt.Capacity {
+		return
+	}
+
+	node.items = make(map[int]aoiPos, count)
+	for _, child := range node.children {
+		for id, p := range child.items {
+			node.items[id] = p
+		}
+	}
+	node.children = nil
+}
+
+// 打印四叉树信息
+func (qt *QuadTreeAOI) String() string {
+	qt.lock.RLock()
+	defer qt.lock.RUnlock()
+
+	str := fmt.Sprintf(
+		"QuadTreeAOI:\nMinX:%d, MaxX:%d, MinY:%d, MaxY:%d, Radius:%f\n",
+		qt.MinX, qt.MaxX, qt.MinY, qt.MaxY, qt.Radius,
+	)
+
+	var walk func(node *quadNode)
+	walk = func(node *quadNode) {
+		if node.isLeaf() {
+			str += fmt.Sprintf(
+				"depth:%d, minX:%f, maxX:%f, minY:%f, maxY:%f, players:%+v\n",
+				node.depth, node.minX, node.maxX, node.minY, node.maxY, node.items,
+			)
+			return
+		}
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(qt.root)
+
+	return str
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 )
 
@@ -20,3 +21,156 @@ func TestGetSurroundGridsByGid(t *testing.T) {
 	// 打印
 	fmt.Println(aoiMgr.GetSurroundGridsByGid(4))
 }
+
+func TestDiffSurroundGrids(t *testing.T) {
+	// 初始化 AOIManager，每个格子 50x50
+	aoiMgr := NewAOIManager(0, 250, 5, 0, 250, 5)
+
+	// 从格子 6 移动到格子 7，x=0 这一列离开视野，x=3 这一列进入视野
+	leaveGrids, enterGrids := aoiMgr.DiffSurroundGrids(6, 7)
+	for _, grid := range leaveGrids {
+		if grid.GID%5 != 0 {
+			t.Errorf("grid %d should not leave", grid.GID)
+		}
+	}
+	for _, grid := range enterGrids {
+		if grid.GID%5 != 3 {
+			t.Errorf("grid %d should not enter", grid.GID)
+		}
+	}
+	if len(leaveGrids) != 3 || len(enterGrids) != 3 {
+		t.Errorf("leave=%v, enter=%v", leaveGrids, enterGrids)
+	}
+}
+
+func TestGetGidByPos(t *testing.T) {
+	// 初始化 AOIManager，每个格子 50x50
+	aoiMgr := NewAOIManager(0, 250, 5, 0, 250, 5)
+
+	if gid := aoiMgr.GetGidByPos(120, 60); gid != 7 {
+		t.Errorf("gid=%d, want 7", gid)
+	}
+}
+
+func TestGetPidsByRadius(t *testing.T) {
+	// 初始化 AOIManager，每个格子 50x50
+	aoiMgr := NewAOIManager(0, 250, 5, 0, 250, 5)
+	aoiMgr.SetRadius(30)
+
+	aoiMgr.AddPidToGridByPos(1, 100, 100)
+	aoiMgr.AddPidToGridByPos(2, 120, 120) // 距离约 28.3，在视野内
+	aoiMgr.AddPidToGridByPos(3, 125, 125) // 距离约 35.4，同一个格子但不在视野内
+	aoiMgr.AddPidToGridByPos(4, 40, 100)  // 距离 60，不在视野内
+
+	pids := aoiMgr.GetPidsByPos(100, 100)
+	if len(pids) != 2 {
+		t.Fatalf("pids=%v, want [1 2]", pids)
+	}
+	for _, pid := range pids {
+		if pid != 1 && pid != 2 {
+			t.Errorf("pid %d should not be visible", pid)
+		}
+	}
+}
+
+func TestCrossListAOI(t *testing.T) {
+	aoi := NewCrossListAOI(0, 250, 0, 250, 30)
+
+	aoi.Add(1, 100, 100)
+	aoi.Add(2, 120, 120) // 距离约 28.3，在视野内
+	aoi.Add(3, 125, 125) // 距离约 35.4，不在视野内
+	aoi.Add(4, 40, 100)  // 距离 60，不在视野内
+
+	if pids := aoi.GetPidsByPos(100, 100); len(pids) != 2 {
+		t.Fatalf("pids=%v, want [1 2]", pids)
+	}
+
+	// 玩家 1 移动到玩家 4 的旁边，玩家 2 离开视野，玩家 4 进入视野
+	leavePids, enterPids := aoi.Move(1, 50, 100)
+	if len(leavePids) != 1 || leavePids[0] != 2 {
+		t.Errorf("leavePids=%v, want [2]", leavePids)
+	}
+	if len(enterPids) != 1 || enterPids[0] != 4 {
+		t.Errorf("enterPids=%v, want [4]", enterPids)
+	}
+
+	aoi.Remove(4)
+	if pids := aoi.GetPidsByPos(50, 100); len(pids) != 1 {
+		t.Errorf("pids=%v, want [1]", pids)
+	}
+
+	fmt.Println(aoi)
+}
+
+// benchmarkAOIMove 在 AOI 中放入一批玩家，然后让玩家不断小范围移动
+func benchmarkAOIMove(b *testing.B, aoi IAOI) {
+	const players = 1000
+	rnd := rand.New(rand.NewSource(1))
+	pos := make([][2]float32, players)
+	for pid := range pos {
+		pos[pid] = [2]float32{float32(rnd.Intn(1000)), float32(rnd.Intn(1000))}
+		aoi.Add(pid, pos[pid][0], pos[pid][1])
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pid := i % players
+		x := pos[pid][0] + float32(rnd.Intn(11)-5)
+		y := pos[pid][1] + float32(rnd.Intn(11)-5)
+		if x < 0 || x >= 1000 || y < 0 || y >= 1000 {
+			continue
+		}
+		pos[pid] = [2]float32{x, y}
+		aoi.Move(pid, x, y)
+	}
+}
+
+func BenchmarkGridAOIMove(b *testing.B) {
+	benchmarkAOIMove(b, NewAOI(AOIModeGrid, 0, 1000, 20, 0, 1000, 20, 0))
+}
+
+func BenchmarkCrossListAOIMove(b *testing.B) {
+	benchmarkAOIMove(b, NewAOI(AOIModeCrossList, 0, 1000, 20, 0, 1000, 20, 0))
+}
+
+func TestQuadTreeAOI(t *testing.T) {
+	aoi := NewQuadTreeAOI(0, 10000, 0, 10000, 30)
+
+	// 在角落放入一批玩家，使四叉树在这里分裂
+	for pid := 10; pid < 40; pid++ {
+		aoi.Add(pid, float32(pid), float32(pid))
+	}
+	if pids := aoi.GetPidsByRect(0, 19.5, 0, 19.5); len(pids) != 10 {
+		t.Errorf("rect pids=%v, want 10 players", pids)
+	}
+
+	aoi.Add(1, 5000, 5000)
+	aoi.Add(2, 5020, 5020) // 距离约 28.3，在视野内
+	aoi.Add(3, 5025, 5025) // 距离约 35.4，不在视野内
+	if pids := aoi.GetPidsByPos(5000, 5000); len(pids) != 2 {
+		t.Fatalf("pids=%v, want [1 2]", pids)
+	}
+
+	// 玩家 1 移动到角落，玩家 2 离开视野
+	leavePids, enterPids := aoi.Move(1, 30, 30)
+	if len(leavePids) != 1 || leavePids[0] != 2 {
+		t.Errorf("leavePids=%v, want [2]", leavePids)
+	}
+	if len(enterPids) == 0 {
+		t.Errorf("enterPids should not be empty")
+	}
+
+	// 移除角落的玩家之后，四叉树重新合并
+	for pid := 10; pid < 40; pid++ {
+		aoi.Remove(pid)
+	}
+	if pids := aoi.GetPidsByRect(0, 10000, 0, 10000); len(pids) != 3 {
+		t.Errorf("pids=%v, want [1 2 3]", pids)
+	}
+
+	fmt.Println(aoi)
+}
+
+func BenchmarkQuadTreeAOIMove(b *testing.B) {
+	benchmarkAOIMove(b, NewAOI(AOIModeQuadTree, 0, 1000, 20, 0, 1000, 20, 0))
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 认证方式，对应 AuthConfig.Mode
+const (
+	AuthModeNone = "none" // 不校验凭证，只用于开发环境
+	AuthModeHMAC = "hmac" // 凭证为服务器密钥对账号和过期时间的 HMAC 签名
+	AuthModeFile = "file" // 凭证保存在账号文件中
+)
+
+// AuthInfo 认证通过之后的账号信息
+type AuthInfo struct {
+	Account string // 账号
+	Level   int32  // 账号的权限等级
+}
+
+// Authenticator 校验登录凭证的认证器
+type Authenticator interface {
+	Authenticate(account, token string) (*AuthInfo, error)
+}
+
+// NewAuthenticator 根据世界的配置创建认证器
+func NewAuthenticator(conf *WorldConfig) (Authenticator, error) {
+	switch conf.Auth.Mode {
+	case AuthModeHMAC:
+		return &HMACAuthenticator{
+			Secret: []byte(conf.Auth.Secret),
+			Level:  conf.DefaultPermission,
+		}, nil
+	case AuthModeFile:
+		return NewFileAuthenticator(conf.Auth.AccountFile)
+	default:
+		return &NoneAuthenticator{
+			Level: conf.DefaultPermission,
+		}, nil
+	}
+}
+
+// NoneAuthenticator 不校验凭证，任何非空的账号都可以登录
+type NoneAuthenticator struct {
+	Level int32 // 登录之后的权限等级
+}
+
+// Authenticate 校验账号不为空
+func (a *NoneAuthenticator) Authenticate(account, token string) (*AuthInfo, error) {
+	if account == "" {
+		return nil, ErrAuthFailed
+	}
+
+	return &AuthInfo{Account: account, Level: a.Level}, nil
+}
+
+// HMACAuthenticator 凭证格式为 "过期时间.签名"，签名为 HMAC-SHA256(密钥, "账号.过期时间") 的十六进制
+// 凭证由登录服务器使用同一个密钥签发，游戏服务器不需要保存账号
+type HMACAuthenticator struct {
+	Secret []byte // 签名的密钥
+	Level  int32  // 登录之后的权限等级
+}
+
+// Sign 为账号签发一个在 expire 之前有效的凭证
+func (a *HMACAuthenticator) Sign(account string, expire time.Time) string {
+	ts := strconv.FormatInt(expire.Unix(), 10)
+	return ts + "." + a.mac(account, ts)
+}
+
+// Authenticate 校验凭证的签名以及是否过期
+func (a *HMACAuthenticator) Authenticate(account, token string) (*AuthInfo, error) {
+	if account == "" {
+		return nil, ErrAuthFailed
+	}
+
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return nil, ErrAuthFailed
+	}
+	if !hmac.Equal([]byte(parts[1]), []byte(a.mac(account, parts[0]))) {
+		return nil, ErrAuthFailed
+	}
+
+	expire, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || time.Now().Unix() >= expire {
+		return nil, ErrTokenExpired
+	}
+
+	return &AuthInfo{Account: account, Level: a.Level}, nil
+}
+
+// mac 计算账号和过期时间的签名
+func (a *HMACAuthenticator) mac(account, ts string) string {
+	h := hmac.New(sha256.New, a.Secret)
+	h.Write([]byte(account + "." + ts))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FileAccount 账号文件中的一个账号
+type FileAccount struct {
+	Account   string // 账号
+	TokenHash string // 凭证的 SHA-256 的十六进制
+	Level     int32  // 账号的权限等级
+}
+
+// FileAuthenticator 从账号文件中加载账号和凭证，用于测试以及没有登录服务器的环境，账号文件不要提交到仓库中
+type FileAuthenticator struct {
+	// 账号 map-key=账号，value=账号信息
+	accounts map[string]*FileAccount
+}
+
+// NewFileAuthenticator 从账号文件中加载账号，文件内容为 FileAccount 的 JSON 数组
+func NewFileAuthenticator(path string) (*FileAuthenticator, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []*FileAccount
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return nil, fmt.Errorf("parse account file error: %s", err)
+	}
+
+	a := &FileAuthenticator{
+		accounts: make(map[string]*FileAccount, len(accounts)),
+	}
+	for _, account := range accounts {
+		a.accounts[account.Account] = account
+	}
+
+	return a, nil
+}
+
+// HashToken 计算凭证的 SHA-256，用于生成账号文件
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate 校验账号是否存在以及凭证是否正确
+func (a *FileAuthenticator) Authenticate(account, token string) (*AuthInfo, error) {
+	acc, ok := a.accounts[account]
+	if !ok || !hmac.Equal([]byte(HashToken(token)), []byte(strings.ToLower(acc.TokenHash))) {
+		return nil, ErrAuthFailed
+	}
+
+	return &AuthInfo{Account: acc.Account, Level: acc.Level}, nil
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// MaxChannelNameLen 频道名称的最大长度（字符数）
+const MaxChannelNameLen = 32
+
+// ChannelManager 聊天频道管理模块，玩家加入第一个玩家时创建频道，最后一个玩家离开时销毁频道
+type ChannelManager struct {
+	// 频道中的玩家 map-key=频道名称，value=频道中的玩家集合
+	channels map[string]map[int32]*Player
+
+	// 保护 channels 的锁
+	lock sync.RWMutex
+
+	// 频道中最后一个玩家离开、频道被销毁时调用
+	OnEmpty func(name string)
+}
+
+// NewChannelManager 初始化聊天频道管理模块
+func NewChannelManager() *ChannelManager {
+	return &ChannelManager{
+		channels: make(map[string]map[int32]*Player),
+	}
+}
+
+// ValidChannelName 频道名称是否合法
+func ValidChannelName(name string) bool {
+	return name != "" && utf8.ValidString(name) && utf8.RuneCountInString(name) <= MaxChannelNameLen
+}
+
+// Join 玩家加入频道
+func (cm *ChannelManager) Join(name string, player *Player) error {
+	if !ValidChannelName(name) {
+		return ErrInvalidChannel
+	}
+
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	members, ok := cm.channels[name]
+	if !ok {
+		members = make(map[int32]*Player)
+		cm.channels[name] = members
+	}
+	members[player.Pid] = player
+
+	return nil
+}
+
+// Leave 玩家离开频道
+func (cm *ChannelManager) Leave(name string, pid int32) error {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	members, ok := cm.channels[name]
+	if !ok || members[pid] == nil {
+		return ErrChannelNotJoined
+	}
+
+	delete(members, pid)
+	if len(members) == 0 {
+		cm.destroy(name)
+	}
+
+	return nil
+}
+
+// LeaveAll 玩家离开所有加入的频道，玩家下线时调用
+func (cm *ChannelManager) LeaveAll(pid int32) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	for name, members := range cm.channels {
+		delete(members, pid)
+		if len(members) == 0 {
+			cm.destroy(name)
+		}
+	}
+}
+
+// destroy 销毁没有玩家的频道，调用方需要持有锁
+func (cm *ChannelManager) destroy(name string) {
+	delete(cm.channels, name)
+	if cm.OnEmpty != nil {
+		cm.OnEmpty(name)
+	}
+}
+
+// IsMember 玩家是否加入了频道
+func (cm *ChannelManager) IsMember(name string, pid int32) bool {
+	cm.lock.RLock()
+	defer cm.lock.RUnlock()
+
+	return cm.channels[name][pid] != nil
+}
+
+// Members 获取频道中的全部玩家
+func (cm *ChannelManager) Members(name string) (players []*Player) {
+	cm.lock.RLock()
+	defer cm.lock.RUnlock()
+
+	members := cm.channels[name]
+	players = make([]*Player, 0, len(members))
+	for _, player := range members {
+		players = append(players, player)
+	}
+
+	return players
+}
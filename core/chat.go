@@ -0,0 +1,193 @@
+package core
+
+import (
+	"time"
+
+	"szinx/pb"
+)
+
+// 聊天范围，对应 BroadCast.Tp=1 以及 Chat.Scope
+const (
+	ChatScopeScene   int32 = 1 // 当前场景
+	ChatScopeSay     int32 = 2 // 附近（视野内）
+	ChatScopeWhisper int32 = 3 // 私聊
+	ChatScopeWorld   int32 = 4 // 世界
+	ChatScopeChannel int32 = 5 // 频道
+	ChatScopeSystem  int32 = 6 // 系统公告
+)
+
+// Chat 校验并过滤玩家的聊天消息，然后按 Scope 发送给对应范围内的玩家，以 / 开头的内容作为命令执行
+// 校验失败时不发送，返回错误以及禁言剩余的时间
+func (p *Player) Chat(msg *pb.Chat, now time.Time) (time.Duration, error) {
+	// 命令和聊天消息一样受禁言、长度、频率和重复消息的限制，避免通过命令刷屏
+	if remain, err := p.CheckChat(msg.Content, now); err != nil {
+		return remain, err
+	}
+
+	// 以 / 开头的内容作为命令执行，不广播
+	if IsCommand(msg.Content) {
+		p.ExecCommand(msg.Content)
+		return 0, nil
+	}
+
+	// 过滤敏感词等内容之后再发送
+	content, err := p.world.ChatFilters.Filter(msg.Content)
+	if err != nil {
+		return 0, err
+	}
+	msg.Content = content
+
+	switch msg.Scope {
+	case ChatScopeSay:
+		p.Say(msg.Content)
+	case ChatScopeWhisper:
+		return 0, p.Whisper(msg.Target, msg.Content)
+	case ChatScopeWorld:
+		p.WorldTalk(msg.Content)
+	case ChatScopeChannel:
+		return 0, p.ChannelTalk(msg.Channel, msg.Content)
+	default:
+		p.Talk(msg.Content)
+	}
+
+	return 0, nil
+}
+
+// Say 玩家发送附近聊天，发送给视野内的玩家（包括自己）
+func (p *Player) Say(content string) {
+	protoMsg := &pb.Chat{
+		Pid:     p.Pid,
+		Scope:   ChatScopeSay,
+		Content: content,
+	}
+
+	sendChat(p.GetSurroundingPlayers(), protoMsg)
+}
+
+// Whisper 玩家私聊，发送给目标玩家以及自己
+func (p *Player) Whisper(target int32, content string) error {
+	targetPlayer := p.world.GetPlayerByPid(target)
+	if targetPlayer == nil {
+		return ErrPlayerNotFound
+	}
+
+	protoMsg := &pb.Chat{
+		Pid:     p.Pid,
+		Scope:   ChatScopeWhisper,
+		Content: content,
+		Target:  target,
+	}
+
+	// 私聊自己时只发送一次
+	players := []*Player{targetPlayer}
+	if targetPlayer != p {
+		players = append(players, p)
+	}
+	sendChat(players, protoMsg)
+
+	return nil
+}
+
+// WorldTalk 玩家发送世界聊天，发送给全部在线的玩家
+func (p *Player) WorldTalk(content string) {
+	protoMsg := &pb.Chat{
+		Pid:     p.Pid,
+		Scope:   ChatScopeWorld,
+		Content: content,
+	}
+	p.recordChat(protoMsg)
+
+	sendChat(p.world.GetAllPlayers(), protoMsg)
+}
+
+// ChannelTalk 玩家发送频道聊天，只有加入了频道的玩家才能发送
+func (p *Player) ChannelTalk(channel, content string) error {
+	if !p.world.Channels.IsMember(channel, p.Pid) {
+		return ErrChannelNotJoined
+	}
+
+	protoMsg := &pb.Chat{
+		Pid:     p.Pid,
+		Scope:   ChatScopeChannel,
+		Content: content,
+		Channel: channel,
+	}
+	p.recordChat(protoMsg)
+
+	sendChat(p.world.Channels.Members(channel), protoMsg)
+
+	return nil
+}
+
+// JoinChannel 玩家加入频道，并发送频道最近的聊天记录
+func (p *Player) JoinChannel(channel string) error {
+	if err := p.world.Channels.Join(channel, p); err != nil {
+		return err
+	}
+
+	return p.SendChatHistory(ChatScopeChannel, channel, 0, 0)
+}
+
+// LeaveChannel 玩家离开频道
+func (p *Player) LeaveChannel(channel string) error {
+	return p.world.Channels.Leave(channel, p.Pid)
+}
+
+// SendChatResult 将聊天失败的原因发送给客户端，被禁言时带上禁言剩余的时间
+func (p *Player) SendChatResult(remain time.Duration, err error) {
+	// 组建 MsgID:210 的 proto 数据
+	protoMsg := &pb.ChatResult{
+		Code:   ChatCode(err),
+		Msg:    err.Error(),
+		Remain: int32(remain / time.Millisecond),
+	}
+
+	p.SendMsg(210, protoMsg)
+}
+
+// recordChat 记录聊天消息的发送时间，并保存到对应聊天范围的聊天记录中
+func (p *Player) recordChat(protoMsg *pb.Chat) {
+	protoMsg.Time = time.Now().UnixNano() / int64(time.Millisecond)
+	p.world.ChatHistory.Append(HistoryKey(protoMsg.Scope, p.SceneID, protoMsg.Channel), protoMsg)
+}
+
+// SyncChatHistory 玩家上线之后，发送世界和当前场景最近的聊天记录
+func (p *Player) SyncChatHistory() {
+	p.SendChatHistory(ChatScopeWorld, "", 0, 0)
+	p.SendChatHistory(ChatScopeScene, "", 0, 0)
+}
+
+// SendChatHistory 将序号小于 before 的最近 limit 条聊天记录发送给客户端
+// before 为 0 时发送最新的记录，limit 不合法时使用配置的每页数量，频道的记录只有频道中的玩家才能查询
+func (p *Player) SendChatHistory(scope int32, channel string, before uint64, limit int) error {
+	key := HistoryKey(scope, p.SceneID, channel)
+	if key == "" {
+		return ErrNoChatHistory
+	}
+	if scope == ChatScopeChannel && !p.world.Channels.IsMember(channel, p.Pid) {
+		return ErrChannelNotJoined
+	}
+
+	pageSize := p.world.Config.Chat.HistoryPageSize
+	if limit <= 0 || limit > pageSize {
+		limit = pageSize
+	}
+	msgs, more := p.world.ChatHistory.Page(key, before, limit)
+
+	// 组建 MsgID:211 的 proto 数据
+	p.SendMsg(211, &pb.ChatHistory{
+		Scope:   scope,
+		Channel: channel,
+		Msgs:    msgs,
+		More:    more,
+	})
+
+	return nil
+}
+
+// sendChat 将 MsgID:209 的聊天消息发送给一组玩家
+func sendChat(players []*Player, protoMsg *pb.Chat) {
+	for _, player := range players {
+		player.SendMsg(209, protoMsg)
+	}
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ChatFilter 聊天内容过滤器，返回过滤之后的内容，消息不允许发送时返回错误
+type ChatFilter interface {
+	Filter(content string) (string, error)
+}
+
+// FilterChain 按顺序执行的一组聊天内容过滤器
+type FilterChain []ChatFilter
+
+// Filter 依次执行每个过滤器，任意一个过滤器返回错误时停止
+func (fc FilterChain) Filter(content string) (string, error) {
+	for _, f := range fc {
+		var err error
+		if content, err = f.Filter(content); err != nil {
+			return "", err
+		}
+	}
+
+	return content, nil
+}
+
+// NewChatFilterChain 根据聊天的配置创建过滤器链：Unicode 规范化、屏蔽网址、屏蔽敏感词
+// 配置了敏感词文件时同时返回敏感词过滤器，需要调用 Start 加载词表
+func NewChatFilterChain(conf ChatConfig) (FilterChain, *WordFilter) {
+	chain := FilterChain{NormalizeFilter{}}
+	if conf.BlockURL {
+		chain = append(chain, URLFilter{})
+	}
+
+	var wf *WordFilter
+	if conf.WordListFile != "" {
+		wf = NewWordFilter(conf.WordListFile, conf.WordListReload)
+		chain = append(chain, wf)
+	}
+
+	return chain, wf
+}
+
+// NormalizeFilter Unicode 规范化：按 NFKC 将全角字符、连字、带圈字母等兼容字符转换为标准字符并组合附加符号，
+// 再去掉控制字符和不可见的格式字符。大小写折叠在敏感词匹配时进行，聊天内容保留原来的大小写
+type NormalizeFilter struct{}
+
+// Filter 规范化聊天内容
+func (NormalizeFilter) Filter(content string) (string, error) {
+	content = norm.NFKC.String(strings.ToValidUTF8(content, ""))
+
+	var b strings.Builder
+	b.Grow(len(content))
+
+	for _, r := range content {
+		// 控制字符（包括换行）和零宽字符等格式字符
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String(), nil
+}
+
+// urlPattern 匹配带协议的网址、www 开头的网址以及常见顶级域名的域名
+var urlPattern = regexp.MustCompile(`(?i)(\b[a-z][a-z0-9+.-]*://\S+|\bwww\.\S+|\b[a-z0-9-]+(\.[a-z0-9-]+)*\.(com|net|org|io|gg|cn|cc|top|xyz|me)\b)`)
+
+// URLFilter 屏蔽包含网址的聊天消息
+type URLFilter struct{}
+
+// Filter 聊天内容包含网址时返回错误
+func (URLFilter) Filter(content string) (string, error) {
+	if urlPattern.MatchString(content) {
+		return "", ErrChatURL
+	}
+
+	return content, nil
+}
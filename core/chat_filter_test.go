@@ -0,0 +1,96 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestACMatcherMask(t *testing.T) {
+	m := newACMatcher([]string{"he", "she", "his", "hers", "外挂"})
+
+	cases := []struct {
+		in, want string
+	}{
+		{"ushers", "u*****"},
+		{"SHE said", "*** said"},
+		{"this", "t***"},
+		{"买外挂吗", "买**吗"},
+		{"hello", "**llo"},
+		{"abc", "abc"},
+	}
+	for _, c := range cases {
+		if got := m.Mask(c.in); got != c.want {
+			t.Errorf("Mask(%q)=%q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestFilterChain(t *testing.T) {
+	wf := NewWordFilter("", 0)
+	wf.SetWords([]string{"bad"})
+	chain := FilterChain{NormalizeFilter{}, URLFilter{}, wf}
+
+	// 全角字符和零宽字符不能绕过敏感词
+	if got, err := chain.Filter("ｂ​ａｄ\tword"); err != nil || got != "***word" {
+		t.Errorf("got=%q, err=%v", got, err)
+	}
+
+	// 连字、带圈字母、组合附加符号以及不同的大小写形式都按规范化之后的字符匹配，其它内容保留原来的大小写
+	wf.SetWords([]string{"bad", "fix", "café", "σοφος"})
+	cases := []struct {
+		in, want string
+	}{
+		{"ⓑⒶⓓ Day", "*** Day"},
+		{"ﬁx It", "*** It"},
+		{"cafe\u0301", "****"},
+		{"ΣΟΦΟΣ", "*****"},
+	}
+	for _, c := range cases {
+		if got, err := chain.Filter(c.in); err != nil || got != c.want {
+			t.Errorf("Filter(%q)=%q, err=%v, want %q", c.in, got, err, c.want)
+		}
+	}
+
+	for _, content := range []string{"go to http://x.y/z", "www.example", "visit shop.example.com now"} {
+		if _, err := chain.Filter(content); err != ErrChatURL {
+			t.Errorf("Filter(%q) err=%v, want ErrChatURL", content, err)
+		}
+	}
+	if got, err := chain.Filter("ok. fine"); err != nil || got != "ok. fine" {
+		t.Errorf("got=%q, err=%v", got, err)
+	}
+}
+
+func TestWordFilterReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "words")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "words.txt")
+	if err := ioutil.WriteFile(path, []byte("# comment\nfoo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wf := NewWordFilter(path, 0)
+	if err := wf.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := wf.Filter("foo bar"); got != "*** bar" {
+		t.Errorf("got=%q", got)
+	}
+
+	// 词表文件修改之后重新加载
+	if err := ioutil.WriteFile(path, []byte("bar\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+	wf.reloadIfModified()
+	if got, _ := wf.Filter("foo bar"); got != "foo ***" {
+		t.Errorf("got=%q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"szinx/pb"
+)
+
+// chatRing 固定容量的聊天记录环形缓冲区，写满之后覆盖最旧的记录
+type chatRing struct {
+	msgs  []*pb.Chat // 聊天记录
+	start int        // 最旧的记录的下标
+	size  int        // 当前的记录数量
+	seq   uint64     // 最后一条记录的序号
+}
+
+// at 按从旧到新的顺序取出第 i 条记录
+func (r *chatRing) at(i int) *pb.Chat {
+	return r.msgs[(r.start+i)%len(r.msgs)]
+}
+
+// ChatHistory 按聊天范围保存最近的聊天记录，场景、世界和每个频道各自独立
+type ChatHistory struct {
+	// 每个聊天范围保存的最大记录数量，为 0 时不保存
+	Size int
+
+	// 聊天记录 map-key=聊天范围，value=环形缓冲区
+	rings map[string]*chatRing
+
+	// 保护 rings 的锁
+	lock sync.RWMutex
+}
+
+// NewChatHistory 初始化聊天记录，每个聊天范围最多保存 size 条
+func NewChatHistory(size int) *ChatHistory {
+	return &ChatHistory{
+		Size:  size,
+		rings: make(map[string]*chatRing),
+	}
+}
+
+// HistoryKey 聊天范围对应的聊天记录，只有场景、世界和频道聊天保存记录，系统公告保存在世界的记录中
+// 其它聊天范围返回空字符串
+func HistoryKey(scope, sceneID int32, channel string) string {
+	switch scope {
+	case ChatScopeScene:
+		return fmt.Sprintf("scene:%d", sceneID)
+	case ChatScopeWorld, ChatScopeSystem:
+		return "world"
+	case ChatScopeChannel:
+		return "channel:" + channel
+	default:
+		return ""
+	}
+}
+
+// Append 保存一条聊天记录，并为其分配序号
+func (h *ChatHistory) Append(key string, msg *pb.Chat) {
+	if h.Size <= 0 || key == "" {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	r, ok := h.rings[key]
+	if !ok {
+		r = &chatRing{msgs: make([]*pb.Chat, h.Size)}
+		h.rings[key] = r
+	}
+
+	r.seq++
+	msg.Seq = r.seq
+	if r.size < len(r.msgs) {
+		r.msgs[(r.start+r.size)%len(r.msgs)] = msg
+		r.size++
+		return
+	}
+	r.msgs[r.start] = msg
+	r.start = (r.start + 1) % len(r.msgs)
+}
+
+// Page 查询序号小于 before 的最近 limit 条记录，before 为 0 时查询最新的记录
+// 返回的记录按序号从旧到新排列，more 表示是否还有更早的记录
+func (h *ChatHistory) Page(key string, before uint64, limit int) (msgs []*pb.Chat, more bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	r, ok := h.rings[key]
+	if !ok || limit <= 0 {
+		return nil, false
+	}
+
+	// 找到第一条序号不小于 before 的记录，记录的序号是连续的
+	end := r.size
+	if before > 0 && before <= r.seq {
+		oldest := r.seq - uint64(r.size) + 1
+		if before <= oldest {
+			return nil, false
+		}
+		end = int(before - oldest)
+	}
+
+	begin := end - limit
+	if begin < 0 {
+		begin = 0
+	}
+	msgs = make([]*pb.Chat, 0, end-begin)
+	for i := begin; i < end; i++ {
+		msgs = append(msgs, r.at(i))
+	}
+
+	return msgs, begin > 0
+}
+
+// Remove 删除一个聊天范围的全部记录，频道解散或者副本销毁时调用
+func (h *ChatHistory) Remove(key string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	delete(h.rings, key)
+}
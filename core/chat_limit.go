@@ -0,0 +1,90 @@
+package core
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// chatLimiter 玩家聊天的频率限制和禁言状态
+type chatLimiter struct {
+	// 令牌桶中剩余的令牌数量，以及上一次计算令牌的时间
+	tokens   float64
+	refilled time.Time
+
+	// 上一次发送的消息以及发送的时间，用于过滤重复的消息
+	lastContent string
+	lastTime    time.Time
+
+	// 禁言结束的时间，零值表示没有被禁言
+	mutedUntil time.Time
+}
+
+// CheckChat 校验玩家是否可以发送这条聊天消息：是否被禁言、消息是否过长、发送是否过快、是否重复发送
+// 校验失败时返回错误以及禁言剩余的时间，校验通过时消耗一个令牌
+func (p *Player) CheckChat(content string, now time.Time) (time.Duration, error) {
+	conf := p.world.Config.Chat
+
+	p.chatLock.Lock()
+	defer p.chatLock.Unlock()
+	c := &p.chat
+
+	// 1.被禁言
+	if now.Before(c.mutedUntil) {
+		return c.mutedUntil.Sub(now), ErrMuted
+	}
+
+	// 2.消息过长
+	if utf8.RuneCountInString(content) > conf.MaxLength {
+		return 0, ErrChatTooLong
+	}
+
+	// 3.按时间恢复令牌，没有令牌时发送过快
+	if c.refilled.IsZero() {
+		c.tokens = float64(conf.RateBurst)
+	} else if elapsed := now.Sub(c.refilled); elapsed > 0 {
+		c.tokens += elapsed.Seconds() * conf.RatePerSecond
+		if c.tokens > float64(conf.RateBurst) {
+			c.tokens = float64(conf.RateBurst)
+		}
+	}
+	c.refilled = now
+	if c.tokens < 1 {
+		return 0, ErrChatTooFast
+	}
+
+	// 4.一段时间内重复发送相同的消息
+	window := time.Duration(conf.DuplicateWindow) * time.Second
+	if window > 0 && content == c.lastContent && now.Sub(c.lastTime) < window {
+		return 0, ErrChatDuplicate
+	}
+
+	c.tokens--
+	c.lastContent = content
+	c.lastTime = now
+
+	return 0, nil
+}
+
+// Mute 禁言玩家，d 为禁言的时长
+func (p *Player) Mute(d time.Duration, now time.Time) {
+	p.chatLock.Lock()
+	defer p.chatLock.Unlock()
+
+	p.chat.mutedUntil = now.Add(d)
+}
+
+// Unmute 解除玩家的禁言
+func (p *Player) Unmute() {
+	p.chatLock.Lock()
+	defer p.chatLock.Unlock()
+
+	p.chat.mutedUntil = time.Time{}
+}
+
+// MutedUntil 玩家禁言结束的时间，没有被禁言时为零值
+func (p *Player) MutedUntil() time.Time {
+	p.chatLock.Lock()
+	defer p.chatLock.Unlock()
+
+	return p.chat.mutedUntil
+}
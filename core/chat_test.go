@@ -0,0 +1,215 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"szinx/pb"
+)
+
+func TestPlayerChatScopes(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	scene := world.DefaultScene()
+	conn1, conn2, conn3 := &fakeConn{}, &fakeConn{}, &fakeConn{}
+	p1, p2, p3 := newTestPlayer(t, world, conn1, scene), newTestPlayer(t, world, conn2, scene), newTestPlayer(t, world, conn3, scene)
+	world.AddPlayer(p1)
+	world.AddPlayer(p2)
+	world.AddPlayer(p3)
+
+	// p3 离开 p1 的视野，附近聊天收不到，世界聊天可以收到
+	p3.UpdatePos(400, 0, 400, 0)
+	p1.Say("hi")
+	if conn2.count(209) != 1 || conn3.count(209) != 0 {
+		t.Errorf("say count p2=%d p3=%d", conn2.count(209), conn3.count(209))
+	}
+	p1.WorldTalk("hello")
+	if conn3.count(209) != 1 {
+		t.Errorf("world count p3=%d, want 1", conn3.count(209))
+	}
+
+	// 私聊只发送给目标和自己
+	if err := p1.Whisper(p3.Pid, "psst"); err != nil {
+		t.Fatal(err)
+	}
+	msg := &pb.Chat{}
+	if !conn3.last(209, msg) || msg.Scope != ChatScopeWhisper || msg.Target != p3.Pid || conn2.count(209) != 2 {
+		t.Errorf("msg=%v, p2 count=%d", msg, conn2.count(209))
+	}
+	if err := p1.Whisper(9999, "psst"); err != ErrPlayerNotFound {
+		t.Errorf("err=%v, want ErrPlayerNotFound", err)
+	}
+
+	// 只有加入了频道的玩家才能发送和收到频道聊天
+	if err := p1.ChannelTalk("trade", "wts"); err != ErrChannelNotJoined {
+		t.Errorf("err=%v, want ErrChannelNotJoined", err)
+	}
+	if err := p1.JoinChannel(""); err != ErrInvalidChannel {
+		t.Errorf("err=%v, want ErrInvalidChannel", err)
+	}
+	p1.JoinChannel("trade")
+	p3.JoinChannel("trade")
+	if err := p1.ChannelTalk("trade", "wts"); err != nil {
+		t.Fatal(err)
+	}
+	msg = &pb.Chat{}
+	if !conn3.last(209, msg) || msg.Channel != "trade" || conn2.count(209) != 2 {
+		t.Errorf("msg=%v, p2 count=%d", msg, conn2.count(209))
+	}
+
+	// 离开频道以及下线之后不再是频道成员
+	if err := p1.LeaveChannel("trade"); err != nil || p1.LeaveChannel("trade") != ErrChannelNotJoined {
+		t.Errorf("err=%v", err)
+	}
+	p3.Offline()
+	if len(world.Channels.Members("trade")) != 0 {
+		t.Error("channel should be empty")
+	}
+}
+
+func TestPlayerCheckChat(t *testing.T) {
+	conf := DefaultWorldConfig()
+	conf.Chat = ChatConfig{MaxLength: 5, RateBurst: 2, RatePerSecond: 1, DuplicateWindow: 10}
+	world := NewWorldManager(conf)
+	conn := &fakeConn{}
+	player := newTestPlayer(t, world, conn, world.DefaultScene())
+	world.AddPlayer(player)
+	now := time.Now()
+
+	// 按字符数限制长度
+	if _, err := player.CheckChat("你好你好你好", now); err != ErrChatTooLong {
+		t.Errorf("err=%v, want ErrChatTooLong", err)
+	}
+
+	// 令牌用完之后发送过快，1 秒之后恢复一个令牌
+	if _, err := player.CheckChat("a", now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := player.CheckChat("b", now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := player.CheckChat("c", now); err != ErrChatTooFast {
+		t.Errorf("err=%v, want ErrChatTooFast", err)
+	}
+	now = now.Add(time.Second)
+
+	// 重复的消息被过滤，超过时间窗口之后可以再次发送
+	if _, err := player.CheckChat("b", now); err != ErrChatDuplicate {
+		t.Errorf("err=%v, want ErrChatDuplicate", err)
+	}
+	if _, err := player.CheckChat("b", now.Add(10*time.Second)); err != nil {
+		t.Errorf("err=%v, want nil", err)
+	}
+
+	// 被禁言时不广播，告知发送者剩余的禁言时间
+	player.Mute(time.Minute, now)
+	remain, err := player.Chat(&pb.Chat{Scope: ChatScopeWorld, Content: "x"}, now.Add(20*time.Second))
+	if err != ErrMuted || remain != 40*time.Second || conn.count(209) != 0 {
+		t.Errorf("remain=%v, err=%v, count=%d", remain, err, conn.count(209))
+	}
+	player.SendChatResult(remain, err)
+	result := &pb.ChatResult{}
+	if !conn.last(210, result) || result.Code != ChatCodeMuted || result.Remain != 40000 {
+		t.Errorf("result=%v", result)
+	}
+
+	// 禁言到期之后可以发送
+	if _, err := player.Chat(&pb.Chat{Scope: ChatScopeWorld, Content: "x"}, now.Add(time.Minute)); err != nil || conn.count(209) != 1 {
+		t.Errorf("err=%v, count=%d", err, conn.count(209))
+	}
+}
+
+func TestPlayerCheckCommand(t *testing.T) {
+	conf := DefaultWorldConfig()
+	conf.Chat = ChatConfig{MaxLength: 10, RateBurst: 2, RatePerSecond: 1, DuplicateWindow: 10}
+	world := NewWorldManager(conf)
+	conn := &fakeConn{}
+	player := newTestPlayer(t, world, conn, world.DefaultScene())
+	world.AddPlayer(player)
+	now := time.Now()
+
+	// 命令同样受频率限制，不能通过命令刷屏
+	for _, content := range []string{"/help", "/help 1"} {
+		if _, err := player.Chat(&pb.Chat{Content: content}, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := player.Chat(&pb.Chat{Content: "/help 2"}, now); err != ErrChatTooFast || conn.count(212) != 2 {
+		t.Errorf("err=%v, count=%d", err, conn.count(212))
+	}
+
+	// 被禁言时不能执行命令
+	player.Mute(time.Minute, now)
+	if _, err := player.Chat(&pb.Chat{Content: "/help 3"}, now.Add(time.Second)); err != ErrMuted || conn.count(212) != 2 {
+		t.Errorf("err=%v, count=%d", err, conn.count(212))
+	}
+}
+
+func TestChatHistoryPage(t *testing.T) {
+	h := NewChatHistory(5)
+	for i := 0; i < 8; i++ {
+		h.Append("world", &pb.Chat{Content: string(rune('a' + i))})
+	}
+
+	// 只保留最近的 5 条记录 d-h，序号 4-8
+	msgs, more := h.Page("world", 0, 3)
+	if len(msgs) != 3 || msgs[0].Content != "f" || msgs[2].Seq != 8 || !more {
+		t.Errorf("msgs=%v, more=%v", msgs, more)
+	}
+
+	// 向前翻页
+	msgs, more = h.Page("world", msgs[0].Seq, 3)
+	if len(msgs) != 2 || msgs[0].Content != "d" || msgs[1].Content != "e" || more {
+		t.Errorf("msgs=%v, more=%v", msgs, more)
+	}
+	if msgs, _ = h.Page("world", 4, 3); len(msgs) != 0 {
+		t.Errorf("msgs=%v, want empty", msgs)
+	}
+	if msgs, _ = h.Page("scene:1", 0, 3); len(msgs) != 0 {
+		t.Errorf("msgs=%v, want empty", msgs)
+	}
+}
+
+func TestPlayerChatHistory(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	scene := world.DefaultScene()
+	conn1, conn2 := &fakeConn{}, &fakeConn{}
+	p1 := newTestPlayer(t, world, conn1, scene)
+	world.AddPlayer(p1)
+
+	p1.Talk("scene")
+	p1.WorldTalk("world")
+	p1.JoinChannel("trade")
+	p1.ChannelTalk("trade", "channel")
+
+	// 新上线的玩家收到世界和场景的聊天记录
+	p2 := newTestPlayer(t, world, conn2, scene)
+	world.AddPlayer(p2)
+	p2.SyncChatHistory()
+	if conn2.count(211) != 2 {
+		t.Fatalf("count=%d, want 2", conn2.count(211))
+	}
+	msg := &pb.ChatHistory{}
+	if !conn2.last(211, msg) || msg.Scope != ChatScopeScene || len(msg.Msgs) != 1 || msg.Msgs[0].Content != "scene" || msg.Msgs[0].Time == 0 {
+		t.Errorf("msg=%v", msg)
+	}
+
+	// 加入频道之后才能收到频道的聊天记录
+	if err := p2.SendChatHistory(ChatScopeChannel, "trade", 0, 0); err != ErrChannelNotJoined {
+		t.Errorf("err=%v, want ErrChannelNotJoined", err)
+	}
+	p2.JoinChannel("trade")
+	msg = &pb.ChatHistory{}
+	if !conn2.last(211, msg) || msg.Channel != "trade" || len(msg.Msgs) != 1 || msg.Msgs[0].Content != "channel" {
+		t.Errorf("msg=%v", msg)
+	}
+	if err := p2.SendChatHistory(ChatScopeSay, "", 0, 0); err != ErrNoChatHistory {
+		t.Errorf("err=%v, want ErrNoChatHistory", err)
+	}
+
+	// 频道解散之后删除聊天记录
+	p1.LeaveChannel("trade")
+	p2.LeaveChannel("trade")
+	if msgs, _ := world.ChatHistory.Page(HistoryKey(ChatScopeChannel, 0, "trade"), 0, 10); len(msgs) != 0 {
+		t.Errorf("msgs=%v, want empty", msgs)
+	}
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"szinx/pb"
+)
+
+// 玩家的权限等级，等级高的玩家可以执行等级低的命令
+const (
+	PermPlayer int32 = 0 // 普通玩家
+	PermGM     int32 = 1 // GM
+	PermAdmin  int32 = 2 // 管理员
+)
+
+// CommandPrefix 聊天内容以此开头时作为命令执行，不会广播
+const CommandPrefix = "/"
+
+// CommandHandler 命令的处理函数，args 为命令名称之后的参数，返回发送给执行者的结果
+type CommandHandler func(p *Player, args []string) (string, error)
+
+// Command 聊天命令
+type Command struct {
+	Name    string         // 命令名称，不包括 /
+	Level   int32          // 执行命令需要的权限等级
+	Usage   string         // 命令的用法，参数错误时提示给执行者
+	Handler CommandHandler // 命令的处理函数
+}
+
+// CommandRegistry 聊天命令的注册表
+type CommandRegistry struct {
+	// 已注册的命令 map-key=命令名称，value=命令
+	commands map[string]*Command
+
+	// 保护 commands 的锁
+	lock sync.RWMutex
+}
+
+// NewCommandRegistry 初始化命令注册表，并注册内置的命令
+func NewCommandRegistry() *CommandRegistry {
+	r := &CommandRegistry{
+		commands: make(map[string]*Command),
+	}
+	registerBuiltinCommands(r)
+
+	return r
+}
+
+// Register 注册一个命令，同名的命令会被替换
+func (r *CommandRegistry) Register(cmd *Command) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.commands[strings.ToLower(cmd.Name)] = cmd
+}
+
+// Get 通过命令名称查询命令，名称不区分大小写
+func (r *CommandRegistry) Get(name string) *Command {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.commands[strings.ToLower(name)]
+}
+
+// Available 获取权限等级为 level 的玩家可以执行的命令，按名称排序
+func (r *CommandRegistry) Available(level int32) []*Command {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	cmds := make([]*Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		if cmd.Level <= level {
+			cmds = append(cmds, cmd)
+		}
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].Name < cmds[j].Name
+	})
+
+	return cmds
+}
+
+// Execute 解析并执行一行命令，返回命令名称以及执行的结果
+func (r *CommandRegistry) Execute(p *Player, line string) (name, result string, err error) {
+	fields := strings.Fields(strings.TrimPrefix(line, CommandPrefix))
+	if len(fields) == 0 {
+		return "", "", ErrUnknownCommand
+	}
+	name = strings.ToLower(fields[0])
+
+	cmd := r.Get(name)
+	if cmd == nil {
+		return name, "", ErrUnknownCommand
+	}
+	if p.PermLevel() < cmd.Level {
+		return name, "", ErrPermissionDenied
+	}
+
+	result, err = cmd.Handler(p, fields[1:])
+	if err == ErrCommandUsage {
+		return name, "usage: " + CommandPrefix + cmd.Name + " " + cmd.Usage, err
+	}
+
+	return name, result, err
+}
+
+// IsCommand 聊天内容是否为命令
+func IsCommand(content string) bool {
+	return strings.HasPrefix(content, CommandPrefix)
+}
+
+// ExecCommand 执行玩家在聊天中输入的命令，并将执行结果只发送给执行者
+func (p *Player) ExecCommand(line string) {
+	name, result, err := p.world.Commands.Execute(p, line)
+
+	// 组建 MsgID:212 的 proto 数据
+	protoMsg := &pb.CommandResult{
+		Command: name,
+		Code:    CommandCode(err),
+		Msg:     result,
+	}
+	if err != nil {
+		fmt.Printf("Player pid=%d command %q failed: %s\n", p.Pid, line, err)
+		if protoMsg.Msg == "" {
+			protoMsg.Msg = err.Error()
+		}
+	}
+
+	p.SendMsg(212, protoMsg)
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"szinx/pb"
+)
+
+// registerBuiltinCommands 注册内置的命令
+func registerBuiltinCommands(r *CommandRegistry) {
+	r.Register(&Command{
+		Name:  "help",
+		Level: PermPlayer,
+		Usage: "",
+		Handler: func(p *Player, args []string) (string, error) {
+			cmds := p.world.Commands.Available(p.PermLevel())
+			lines := make([]string, 0, len(cmds))
+			for _, cmd := range cmds {
+				lines = append(lines, strings.TrimSpace(CommandPrefix+cmd.Name+" "+cmd.Usage))
+			}
+			return strings.Join(lines, "\n"), nil
+		},
+	})
+
+	r.Register(&Command{
+		Name:    "tp",
+		Level:   PermGM,
+		Usage:   "<x> <z> [sceneID]",
+		Handler: cmdTeleport,
+	})
+
+	r.Register(&Command{
+		Name:  "home",
+		Level: PermGM,
+		Usage: "[pid]",
+		Handler: func(p *Player, args []string) (string, error) {
+			target, err := cmdManagedTarget(p, args, 0)
+			if err != nil {
+				return "", err
+			}
+			x, z := target.Scene().SpawnPos()
+			if err := target.Teleport(0, x, 0, z, 0); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("player %d is back to spawn (%.1f,%.1f)", target.Pid, x, z), nil
+		},
+	})
+
+	r.Register(&Command{
+		Name:  "instance",
+		Level: PermGM,
+		Usage: "<templateID>",
+		Handler: func(p *Player, args []string) (string, error) {
+			if len(args) != 1 {
+				return "", ErrCommandUsage
+			}
+			templateID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return "", ErrCommandUsage
+			}
+			scene, err := p.world.InstanceMgr.CreateInstance(int32(templateID))
+			if err != nil {
+				return "", err
+			}
+			if err := p.world.InstanceMgr.EnterInstance(scene, []*Player{p}); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("entered instance %s", scene.Name), nil
+		},
+	})
+
+	r.Register(&Command{
+		Name:  "kick",
+		Level: PermGM,
+		Usage: "<pid>",
+		Handler: func(p *Player, args []string) (string, error) {
+			if len(args) != 1 {
+				return "", ErrCommandUsage
+			}
+			target, err := cmdManagedTarget(p, args, 0)
+			if err != nil {
+				return "", err
+			}
+			target.Kick()
+			return fmt.Sprintf("player %d is kicked", target.Pid), nil
+		},
+	})
+
+	r.Register(&Command{
+		Name:  "mute",
+		Level: PermGM,
+		Usage: "<pid> <seconds>",
+		Handler: func(p *Player, args []string) (string, error) {
+			if len(args) != 2 {
+				return "", ErrCommandUsage
+			}
+			seconds, err := strconv.Atoi(args[1])
+			if err != nil || seconds <= 0 {
+				return "", ErrCommandUsage
+			}
+			target, err := cmdManagedTarget(p, args, 0)
+			if err != nil {
+				return "", err
+			}
+			target.Mute(time.Duration(seconds)*time.Second, time.Now())
+			return fmt.Sprintf("player %d is muted for %d seconds", target.Pid, seconds), nil
+		},
+	})
+
+	r.Register(&Command{
+		Name:  "unmute",
+		Level: PermGM,
+		Usage: "<pid>",
+		Handler: func(p *Player, args []string) (string, error) {
+			if len(args) != 1 {
+				return "", ErrCommandUsage
+			}
+			target, err := cmdManagedTarget(p, args, 0)
+			if err != nil {
+				return "", err
+			}
+			target.Unmute()
+			return fmt.Sprintf("player %d is unmuted", target.Pid), nil
+		},
+	})
+
+	r.Register(&Command{
+		Name:  "announce",
+		Level: PermGM,
+		Usage: "<message>",
+		Handler: func(p *Player, args []string) (string, error) {
+			if len(args) == 0 {
+				return "", ErrCommandUsage
+			}
+			p.world.Announce(strings.Join(args, " "))
+			return "announced", nil
+		},
+	})
+}
+
+// cmdTeleport 将执行者传送到指定的坐标，没有指定场景时在当前场景中传送
+func cmdTeleport(p *Player, args []string) (string, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return "", ErrCommandUsage
+	}
+
+	x, errX := strconv.ParseFloat(args[0], 32)
+	z, errZ := strconv.ParseFloat(args[1], 32)
+	if errX != nil || errZ != nil {
+		return "", ErrCommandUsage
+	}
+	sceneID := 0
+	if len(args) == 3 {
+		var err error
+		if sceneID, err = strconv.Atoi(args[2]); err != nil {
+			return "", ErrCommandUsage
+		}
+	}
+
+	if err := p.Teleport(int32(sceneID), float32(x), 0, float32(z), p.V); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("teleported to scene %d (%.1f,%.1f)", p.SceneID, p.X, p.Z), nil
+}
+
+// cmdTarget 解析第 i 个参数中的目标玩家ID，没有这个参数时目标为执行者自己
+func cmdTarget(p *Player, args []string, i int) (*Player, error) {
+	if len(args) <= i {
+		return p, nil
+	}
+
+	pid, err := strconv.Atoi(args[i])
+	if err != nil {
+		return nil, ErrCommandUsage
+	}
+	target := p.world.GetPlayerByPid(int32(pid))
+	if target == nil {
+		return nil, ErrPlayerNotFound
+	}
+
+	return target, nil
+}
+
+// cmdManagedTarget 解析第 i 个参数中被管理的目标玩家，指定的目标玩家的权限等级必须低于执行者
+// 没有这个参数时目标为执行者自己
+func cmdManagedTarget(p *Player, args []string, i int) (*Player, error) {
+	target, err := cmdTarget(p, args, i)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > i && target.PermLevel() >= p.PermLevel() {
+		return nil, ErrPermissionDenied
+	}
+
+	return target, nil
+}
+
+// Announce 向全部在线的玩家发送系统公告，并保存到世界的聊天记录中
+func (wm *WorldManager) Announce(content string) {
+	protoMsg := &pb.Chat{
+		Scope:   ChatScopeSystem,
+		Content: content,
+		Time:    time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	wm.ChatHistory.Append(HistoryKey(ChatScopeSystem, 0, ""), protoMsg)
+
+	sendChat(wm.GetAllPlayers(), protoMsg)
+}
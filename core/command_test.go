@@ -0,0 +1,104 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"szinx/pb"
+)
+
+func TestPlayerExecCommand(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	scene := world.DefaultScene()
+	conn1, conn2 := &fakeConn{}, &fakeConn{}
+	gm, player := newTestPlayer(t, world, conn1, scene), newTestPlayer(t, world, conn2, scene)
+	gm.Level = PermGM
+	world.AddPlayer(gm)
+	world.AddPlayer(player)
+
+	result := func(conn *fakeConn) *pb.CommandResult {
+		msg := &pb.CommandResult{}
+		conn.last(212, msg)
+		return msg
+	}
+
+	// 命令不会广播，结果只发送给执行者
+	if _, err := player.Chat(&pb.Chat{Scope: ChatScopeWorld, Content: "/tp 200 200"}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if r := result(conn2); r.Code != CommandCodePermission || r.Command != "tp" || conn1.count(209) != 0 {
+		t.Errorf("result=%v", r)
+	}
+	player.ExecCommand("/nope")
+	if r := result(conn2); r.Code != CommandCodeUnknown {
+		t.Errorf("result=%v", r)
+	}
+
+	// 参数错误时提示用法
+	gm.ExecCommand("/tp 200")
+	if r := result(conn1); r.Code != CommandCodeUsage || r.Msg != "usage: /tp <x> <z> [sceneID]" {
+		t.Errorf("result=%v", r)
+	}
+	gm.ExecCommand("/TP 200 210")
+	if r := result(conn1); r.Code != CommandCodeOK || gm.X != 200 || gm.Z != 210 {
+		t.Errorf("result=%v, pos=(%f,%f)", r, gm.X, gm.Z)
+	}
+	gm.ExecCommand("/tp 9999 0")
+	if r := result(conn1); r.Code != CommandCodeExecuteError || r.Msg != ErrOutOfBounds.Error() {
+		t.Errorf("result=%v", r)
+	}
+
+	// 禁言以及公告
+	gm.ExecCommand("/mute 9999 60")
+	if r := result(conn1); r.Code != CommandCodeExecuteError {
+		t.Errorf("result=%v", r)
+	}
+	gm.ExecCommand("/mute " + pidArg(player.Pid) + " 60")
+	if player.MutedUntil().IsZero() {
+		t.Error("player should be muted")
+	}
+	gm.ExecCommand("/announce server restarts soon")
+	chat := &pb.Chat{}
+	if !conn2.last(209, chat) || chat.Scope != ChatScopeSystem || chat.Content != "server restarts soon" {
+		t.Errorf("chat=%v", chat)
+	}
+
+	// 普通玩家的帮助中只有普通命令
+	player.ExecCommand("/help")
+	if r := result(conn2); r.Msg != "/help" {
+		t.Errorf("result=%v", r)
+	}
+
+	// 不能管理权限等级不低于自己的玩家
+	admin := newTestPlayer(t, world, &fakeConn{}, scene)
+	admin.Level = PermAdmin
+	world.AddPlayer(admin)
+	for _, cmd := range []string{"/kick %d", "/mute %d 60", "/unmute %d", "/home %d"} {
+		gm.ExecCommand(fmt.Sprintf(cmd, admin.Pid))
+		if r := result(conn1); r.Code != CommandCodePermission {
+			t.Errorf("%s result=%v", cmd, r)
+		}
+	}
+	if world.GetPlayerByPid(admin.Pid) != admin || !admin.MutedUntil().IsZero() {
+		t.Error("admin should not be kicked or muted by gm")
+	}
+
+	// 送回出生区域
+	gm.ExecCommand("/home " + pidArg(player.Pid))
+	if r := result(conn1); r.Code != CommandCodeOK || !InAOIBounds(scene.AoiManager, player.X, player.Z) {
+		t.Errorf("result=%v", r)
+	}
+
+	// 踢下线
+	gm.ExecCommand("/kick " + pidArg(player.Pid))
+	if world.GetPlayerByPid(player.Pid) != nil {
+		t.Error("player should be kicked")
+	}
+}
+
+// pidArg 将玩家ID转换为命令的参数
+func pidArg(pid int32) string {
+	return strconv.Itoa(int(pid))
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"math"
+
+	"szinx/pb"
+)
+
+// 坐标量化的参数
+const (
+	QuantSteps       = 65535 // x/z 坐标和角度量化之后的最大值
+	QuantHeightScale = 100   // y 坐标量化的倍数，即精度为 0.01
+)
+
+// quantPos 量化之后的坐标
+type quantPos struct {
+	X, Y, Z, V int32
+}
+
+// deltaBase 观察者上一次同步给客户端的某个玩家的坐标，即服务器上一次发送的坐标，客户端不会确认收到
+type deltaBase struct {
+	// 量化之后的坐标
+	pos quantPos
+	// 上一次发送完整坐标的帧
+	fullFrame uint64
+}
+
+// quantize 按地图边界量化坐标
+func quantize(aoi IAOI, p *pb.Position) quantPos {
+	minX, maxX, minY, maxY := aoi.GetBounds()
+	v := math.Mod(float64(p.V), 360)
+	if v < 0 {
+		v += 360
+	}
+
+	return quantPos{
+		X: quantizeAxis(p.X, minX, maxX),
+		Y: int32(math.Round(float64(p.Y) * QuantHeightScale)),
+		Z: quantizeAxis(p.Z, minY, maxY),
+		V: int32(math.Round(v / 360 * QuantSteps)),
+	}
+}
+
+// quantizeAxis 将 [min, max] 范围内的坐标量化为 [0, QuantSteps]
+func quantizeAxis(f float32, min, max int) int32 {
+	q := math.Round(float64(f-float32(min)) / float64(max-min) * QuantSteps)
+	return int32(math.Max(0, math.Min(QuantSteps, q)))
+}
+
+// buildMoveDeltas 构建发送给观察者的坐标增量，并更新观察者的同步基准
+// 第一次同步、基准被清除或者距离上一次完整同步超过 interval 帧时发送完整坐标
+// 增量以上一次发送的坐标为基准，依赖 TCP 按顺序送达每一条 206 消息，中间丢失一条消息时客户端要到下一次完整同步才能恢复
+func (p *Player) buildMoveDeltas(aoi IAOI, players []*pb.Player, frame uint64, interval int) (ds []*pb.MoveDelta, full bool) {
+	p.deltaLock.Lock()
+	defer p.deltaLock.Unlock()
+
+	if p.deltaBases == nil {
+		p.deltaBases = make(map[int32]*deltaBase)
+	}
+
+	for _, player := range players {
+		pos := quantize(aoi, player.P)
+		base, ok := p.deltaBases[player.Pid]
+
+		// 发送完整坐标
+		if !ok || frame-base.fullFrame >= uint64(interval) {
+			p.deltaBases[player.Pid] = &deltaBase{pos: pos, fullFrame: frame}
+			ds = append(ds, &pb.MoveDelta{
+				Pid: player.Pid,
+				P:   player.P,
+			})
+			full = true
+			continue
+		}
+
+		// 量化之后没有变化的玩家不需要发送
+		if pos == base.pos {
+			continue
+		}
+		ds = append(ds, &pb.MoveDelta{
+			Pid: player.Pid,
+			DX:  pos.X - base.pos.X,
+			DY:  pos.Y - base.pos.Y,
+			DZ:  pos.Z - base.pos.Z,
+			DV:  pos.V - base.pos.V,
+		})
+		base.pos = pos
+	}
+
+	return ds, full
+}
+
+// SendMoveDeltas 将视野内移动过的玩家以坐标增量的方式发送给客户端
+func (p *Player) SendMoveDeltas(aoi IAOI, players []*pb.Player, frame uint64) {
+	ds, full := p.buildMoveDeltas(aoi, players, frame, p.world.Config.FullSyncInterval)
+	if len(ds) == 0 {
+		return
+	}
+
+	// 组建 MsgID:206 的 proto 数据
+	protoMsg := &pb.SyncMoveDelta{
+		Ds: ds,
+	}
+	if full {
+		minX, maxX, minY, maxY := aoi.GetBounds()
+		protoMsg.Q = &pb.QuantInfo{
+			MinX: int32(minX),
+			MaxX: int32(maxX),
+			MinY: int32(minY),
+			MaxY: int32(maxY),
+		}
+	}
+
+	p.SendMsg(206, protoMsg)
+}
+
+// forgetDeltaBase 清除玩家的同步基准，客户端通过 MsgID:201 删除了这个玩家，下一次需要发送完整坐标
+func (p *Player) forgetDeltaBase(pid int32) {
+	p.deltaLock.Lock()
+	defer p.deltaLock.Unlock()
+
+	delete(p.deltaBases, pid)
+}
+
+// clearDeltaBases 清除所有的同步基准，玩家切换场景之后地图边界发生变化
+func (p *Player) clearDeltaBases() {
+	p.deltaLock.Lock()
+	defer p.deltaLock.Unlock()
+
+	p.deltaBases = nil
+}
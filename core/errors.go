@@ -0,0 +1,242 @@
+package core
+
+import "errors"
+
+// 玩家操作失败的原因
+var (
+	ErrSceneNotFound    = errors.New("scene not found")
+	ErrOutOfBounds      = errors.New("position is out of scene bounds")
+	ErrSceneForbidden   = errors.New("scene is not allowed to enter")
+	ErrPortalNotFound   = errors.New("portal not found")
+	ErrPortalTooFar     = errors.New("portal is too far")
+	ErrNotPartyMember   = errors.New("not a member of the instance party")
+	ErrPartyTooLarge    = errors.New("party is too large")
+	ErrInstanceLimit    = errors.New("too many instances created by the leader")
+	ErrNotInInstance    = errors.New("player is not in an instance")
+	ErrTemplateNotFound = errors.New("instance template not found")
+	ErrInvalidPosition  = errors.New("position is NaN or Inf")
+	ErrMoveTooFast      = errors.New("move too fast")
+	ErrStaleMoveSeq     = errors.New("move sequence is stale")
+	ErrActionNotFound   = errors.New("action not found")
+	ErrActionCooldown   = errors.New("action is cooling down")
+	ErrPlayerNotFound   = errors.New("player not found")
+	ErrChannelNotJoined = errors.New("channel is not joined")
+	ErrInvalidChannel   = errors.New("invalid channel name")
+	ErrMuted            = errors.New("player is muted")
+	ErrChatTooLong      = errors.New("chat message is too long")
+	ErrChatTooFast      = errors.New("chat too fast")
+	ErrChatDuplicate    = errors.New("duplicate chat message")
+	ErrChatURL          = errors.New("chat message contains url")
+	ErrNoChatHistory    = errors.New("chat scope has no history")
+	ErrUnknownCommand   = errors.New("unknown command")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrCommandUsage     = errors.New("invalid command arguments")
+	ErrAuthFailed       = errors.New("authentication failed")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrAccountOnline    = errors.New("account is already online")
+	ErrNotLoggedIn      = errors.New("not logged in")
+	ErrAlreadyLoggedIn  = errors.New("connection is already logged in")
+	ErrSessionExpired   = errors.New("session expired")
+	ErrProfileNotFound  = errors.New("profile not found")
+	ErrIDExhausted      = errors.New("id exhausted")
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	ErrServerClosing    = errors.New("server is shutting down")
+)
+
+// 传送结果码，对应 TeleportResult.Code
+const (
+	TeleportCodeOK             int32 = 0  // 成功
+	TeleportCodeSceneNotFound  int32 = 1  // 场景不存在
+	TeleportCodeOutOfBounds    int32 = 2  // 坐标超出地图边界
+	TeleportCodeForbidden      int32 = 3  // 场景不允许进入
+	TeleportCodePortalNotFound int32 = 4  // 传送门不存在
+	TeleportCodePortalTooFar   int32 = 5  // 距离传送门太远
+	TeleportCodePermission     int32 = 6  // 没有指定坐标传送的权限
+	TeleportCodeNoTemplate     int32 = 7  // 副本模板不存在
+	TeleportCodeNotPartyMember int32 = 8  // 不是副本的队伍成员
+	TeleportCodePlayerNotFound int32 = 9  // 队伍成员不在线
+	TeleportCodePartyTooLarge  int32 = 10 // 队伍人数超过上限
+	TeleportCodeInstanceLimit  int32 = 11 // 队长拥有的副本数量超过上限
+	TeleportCodeNotInInstance  int32 = 12 // 不在副本中
+	TeleportCodeInternal       int32 = 13 // 服务器内部错误
+)
+
+// TeleportCode 将传送的错误转换为传送结果码
+func TeleportCode(err error) int32 {
+	switch err {
+	case nil:
+		return TeleportCodeOK
+	case ErrSceneNotFound:
+		return TeleportCodeSceneNotFound
+	case ErrOutOfBounds:
+		return TeleportCodeOutOfBounds
+	case ErrSceneForbidden:
+		return TeleportCodeForbidden
+	case ErrPortalNotFound:
+		return TeleportCodePortalNotFound
+	case ErrPortalTooFar:
+		return TeleportCodePortalTooFar
+	case ErrPermissionDenied:
+		return TeleportCodePermission
+	case ErrTemplateNotFound:
+		return TeleportCodeNoTemplate
+	case ErrNotPartyMember:
+		return TeleportCodeNotPartyMember
+	case ErrPlayerNotFound:
+		return TeleportCodePlayerNotFound
+	case ErrPartyTooLarge:
+		return TeleportCodePartyTooLarge
+	case ErrInstanceLimit:
+		return TeleportCodeInstanceLimit
+	case ErrNotInInstance:
+		return TeleportCodeNotInInstance
+	default:
+		return TeleportCodeInternal
+	}
+}
+
+// 移动纠正码，对应 MoveCorrection.Code
+const (
+	MoveCodeInvalidPosition int32 = 1 // 坐标不合法
+	MoveCodeTooFast         int32 = 2 // 移动速度过快
+	MoveCodeOutOfBounds     int32 = 3 // 坐标超出地图边界
+	MoveCodeInternal        int32 = 4 // 服务器内部错误
+)
+
+// MoveCode 将移动校验的错误转换为移动纠正码
+func MoveCode(err error) int32 {
+	switch err {
+	case ErrInvalidPosition:
+		return MoveCodeInvalidPosition
+	case ErrMoveTooFast:
+		return MoveCodeTooFast
+	case ErrOutOfBounds:
+		return MoveCodeOutOfBounds
+	default:
+		return MoveCodeInternal
+	}
+}
+
+// 动作结果码，对应 ActionResult.Code
+const (
+	ActionCodeNotFound int32 = 1 // 动作不存在
+	ActionCodeCooldown int32 = 2 // 动作冷却中
+	ActionCodeInternal int32 = 3 // 服务器内部错误
+)
+
+// ActionCode 将动作的错误转换为动作结果码
+func ActionCode(err error) int32 {
+	switch err {
+	case ErrActionNotFound:
+		return ActionCodeNotFound
+	case ErrActionCooldown:
+		return ActionCodeCooldown
+	default:
+		return ActionCodeInternal
+	}
+}
+
+// 聊天结果码，对应 ChatResult.Code
+const (
+	ChatCodePlayerNotFound int32 = 1  // 玩家不存在
+	ChatCodeNotJoined      int32 = 2  // 没有加入频道
+	ChatCodeInvalidChannel int32 = 3  // 频道名称不合法
+	ChatCodeMuted          int32 = 4  // 被禁言
+	ChatCodeTooLong        int32 = 5  // 消息过长
+	ChatCodeTooFast        int32 = 6  // 发送过快
+	ChatCodeDuplicate      int32 = 7  // 重复消息
+	ChatCodeURL            int32 = 8  // 包含网址
+	ChatCodeNoHistory      int32 = 9  // 没有聊天记录
+	ChatCodeInternal       int32 = 10 // 服务器内部错误
+)
+
+// ChatCode 将聊天的错误转换为聊天结果码
+func ChatCode(err error) int32 {
+	switch err {
+	case ErrPlayerNotFound:
+		return ChatCodePlayerNotFound
+	case ErrChannelNotJoined:
+		return ChatCodeNotJoined
+	case ErrInvalidChannel:
+		return ChatCodeInvalidChannel
+	case ErrMuted:
+		return ChatCodeMuted
+	case ErrChatTooLong:
+		return ChatCodeTooLong
+	case ErrChatTooFast:
+		return ChatCodeTooFast
+	case ErrChatDuplicate:
+		return ChatCodeDuplicate
+	case ErrChatURL:
+		return ChatCodeURL
+	case ErrNoChatHistory:
+		return ChatCodeNoHistory
+	default:
+		return ChatCodeInternal
+	}
+}
+
+// 命令结果码，对应 CommandResult.Code
+const (
+	CommandCodeOK           int32 = 0 // 成功
+	CommandCodeUnknown      int32 = 1 // 命令不存在
+	CommandCodePermission   int32 = 2 // 权限不足
+	CommandCodeUsage        int32 = 3 // 参数错误
+	CommandCodeExecuteError int32 = 4 // 执行失败
+)
+
+// CommandCode 将命令的错误转换为命令结果码
+func CommandCode(err error) int32 {
+	switch err {
+	case nil:
+		return CommandCodeOK
+	case ErrUnknownCommand:
+		return CommandCodeUnknown
+	case ErrPermissionDenied:
+		return CommandCodePermission
+	case ErrCommandUsage:
+		return CommandCodeUsage
+	default:
+		return CommandCodeExecuteError
+	}
+}
+
+// 登录结果码，对应 LoginResult.Code
+const (
+	LoginCodeOK            int32 = 0 // 成功
+	LoginCodeAuthFailed    int32 = 1 // 认证失败
+	LoginCodeAccountOnline int32 = 2 // 账号已在线
+	LoginCodeNotLoggedIn   int32 = 3 // 未登录
+	LoginCodeAlreadyLogin  int32 = 4 // 重复登录
+	LoginCodeSessionExpire int32 = 5 // 会话已过期
+	LoginCodeClosing       int32 = 6 // 服务器正在关闭
+	LoginCodeTokenExpired  int32 = 7 // 凭证已过期
+	LoginCodeIDExhausted   int32 = 8 // 没有可以分配的玩家ID
+	LoginCodeInternal      int32 = 9 // 服务器内部错误，例如加载玩家数据失败
+)
+
+// LoginCode 将登录的错误转换为登录结果码
+func LoginCode(err error) int32 {
+	switch err {
+	case nil:
+		return LoginCodeOK
+	case ErrAuthFailed:
+		return LoginCodeAuthFailed
+	case ErrAccountOnline:
+		return LoginCodeAccountOnline
+	case ErrNotLoggedIn:
+		return LoginCodeNotLoggedIn
+	case ErrAlreadyLoggedIn:
+		return LoginCodeAlreadyLogin
+	case ErrSessionExpired:
+		return LoginCodeSessionExpire
+	case ErrServerClosing:
+		return LoginCodeClosing
+	case ErrTokenExpired:
+		return LoginCodeTokenExpired
+	case ErrIDExhausted:
+		return LoginCodeIDExhausted
+	default:
+		return LoginCodeInternal
+	}
+}
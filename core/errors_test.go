@@ -0,0 +1,37 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResultCodes(t *testing.T) {
+	unknown := errors.New("disk is full")
+
+	cases := []struct {
+		name string
+		code func(error) int32
+		err  error
+		want int32
+	}{
+		{"teleport", TeleportCode, ErrOutOfBounds, TeleportCodeOutOfBounds},
+		{"teleport", TeleportCode, unknown, TeleportCodeInternal},
+		{"move", MoveCode, ErrOutOfBounds, MoveCodeOutOfBounds},
+		{"move", MoveCode, unknown, MoveCodeInternal},
+		{"action", ActionCode, ErrActionCooldown, ActionCodeCooldown},
+		{"action", ActionCode, unknown, ActionCodeInternal},
+		{"chat", ChatCode, ErrInvalidChannel, ChatCodeInvalidChannel},
+		{"chat", ChatCode, unknown, ChatCodeInternal},
+		{"login", LoginCode, ErrAuthFailed, LoginCodeAuthFailed},
+		{"login", LoginCode, ErrTokenExpired, LoginCodeTokenExpired},
+		{"login", LoginCode, ErrIDExhausted, LoginCodeIDExhausted},
+		{"login", LoginCode, unknown, LoginCodeInternal},
+	}
+
+	// 未知的错误统一返回服务器内部错误，不会被当作某个具体的原因
+	for _, c := range cases {
+		if got := c.code(c.err); got != c.want {
+			t.Errorf("%s code of %q = %d, want %d", c.name, c.err, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+// AOI 视野管理的实现方式
+const (
+	AOIModeGrid      = "grid"      // 格子（九宫格或半径视野）
+	AOIModeCrossList = "crosslist" // 十字链表
+	AOIModeQuadTree  = "quadtree"  // 四叉树
+)
+
+// IAOI AOI 视野管理模块的抽象层
+type IAOI interface {
+	// 将玩家添加到 AOI 的坐标中
+	Add(pid int, x, y float32)
+
+	// 将玩家从 AOI 中移除
+	Remove(pid int)
+
+	// 玩家在 AOI 中移动到新坐标，返回移动后离开视野和进入视野的 playerIDs
+	Move(pid int, x, y float32) (leavePids, enterPids []int)
+
+	// 获取坐标视野内的所有 playerIDs
+	GetPidsByPos(x, y float32) []int
+
+	// 获取 AOI 区域的边界坐标
+	GetBounds() (minX, maxX, minY, maxY int)
+}
+
+// InAOIBounds 坐标是否在 AOI 区域内，区域的右边界和上边界不包含在内
+func InAOIBounds(aoi IAOI, x, y float32) bool {
+	minX, maxX, minY, maxY := aoi.GetBounds()
+	return x >= float32(minX) && x < float32(maxX) && y >= float32(minY) && y < float32(maxY)
+}
+
+// NewAOI 根据实现方式创建 AOI 视野管理模块
+// 十字链表和四叉树没有格子，radius 为 0 时按一个半格子大小的视野近似九宫格
+func NewAOI(mode string, minx, maxx, cntsx, miny, maxy, cntsy int, radius float32) IAOI {
+	if radius <= 0 && mode != AOIModeGrid {
+		edge := (maxx - minx) / cntsx
+		if l := (maxy - miny) / cntsy; l > edge {
+			edge = l
+		}
+		radius = float32(edge) * 1.5
+	}
+
+	switch mode {
+	case AOIModeCrossList:
+		return NewCrossListAOI(minx, maxx, miny, maxy, radius)
+	case AOIModeQuadTree:
+		return NewQuadTreeAOI(minx, maxx, miny, maxy, radius)
+	default:
+		aoiMgr := NewAOIManager(minx, maxx, cntsx, miny, maxy, cntsy)
+		aoiMgr.SetRadius(radius)
+		return aoiMgr
+	}
+}
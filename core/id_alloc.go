@@ -0,0 +1,211 @@
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IDAllocator ID 分配器
+type IDAllocator interface {
+	NextID() (int64, error)
+}
+
+// IDReserver 可以跳过指定 ID 的分配器，从快照恢复玩家之后避免再次分配快照中已经使用的 ID
+type IDReserver interface {
+	// Reserve 之后分配的 ID 都大于 id
+	Reserve(id int64)
+}
+
+// MemoryAllocator 内存中的 ID 计数器，进程重启之后重新从 1 开始，只用于测试以及不需要持久化的环境
+type MemoryAllocator struct {
+	next int64
+	lock sync.Mutex
+}
+
+// NextID 分配下一个 ID
+func (a *MemoryAllocator) NextID() (int64, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.next++
+	return a.next, nil
+}
+
+// Reserve 之后分配的 ID 都大于 id
+func (a *MemoryAllocator) Reserve(id int64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if id > a.next {
+		a.next = id
+	}
+}
+
+// HighWaterAllocator 将已经分配的 ID 上限（高水位）保存在文件中，进程重启之后从上限之后继续分配
+// 每次预留 Step 个 ID 才写一次文件，进程重启时跳过上一次预留而没有用完的 ID
+type HighWaterAllocator struct {
+	Path string // 保存高水位的文件路径
+	Step int64  // 每次预留的 ID 数量
+	Max  int64  // 可以分配的最大 ID
+
+	next int64 // 下一个分配的 ID
+	mark int64 // 已经保存到文件中的高水位，next 超过高水位时需要再次预留
+	lock sync.Mutex
+}
+
+// NewHighWaterAllocator 从文件中读取高水位，文件不存在时从 1 开始分配
+// 创建时立即预留第一批 ID 并写入文件，文件路径不可写时启动就会失败，而不是等到第一个玩家登录
+func NewHighWaterAllocator(path string, step, max int64) (*HighWaterAllocator, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("id step %d must be positive", step)
+	}
+
+	a := &HighWaterAllocator{
+		Path: path,
+		Step: step,
+		Max:  max,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		if a.mark, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return nil, fmt.Errorf("parse id high water mark error: %s", err)
+		}
+	}
+	a.next = a.mark + 1
+
+	if a.next <= a.Max {
+		if err := a.reserve(); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// NextID 分配下一个 ID，需要预留新的 ID 时先将新的高水位写入文件
+func (a *HighWaterAllocator) NextID() (int64, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.next > a.Max {
+		return 0, ErrIDExhausted
+	}
+
+	if a.next > a.mark {
+		if err := a.reserve(); err != nil {
+			return 0, err
+		}
+	}
+
+	id := a.next
+	a.next++
+
+	return id, nil
+}
+
+// Reserve 之后分配的 ID 都大于 id，超过高水位的部分在下一次分配时预留
+func (a *HighWaterAllocator) Reserve(id int64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if id >= a.next {
+		a.next = id + 1
+	}
+}
+
+// reserve 从 next 开始预留 Step 个 ID，将新的高水位写入文件
+func (a *HighWaterAllocator) reserve() error {
+	mark := a.next + a.Step - 1
+	if mark > a.Max {
+		mark = a.Max
+	}
+	if err := a.save(mark); err != nil {
+		return err
+	}
+	a.mark = mark
+
+	return nil
+}
+
+// save 将高水位写入临时文件之后再替换原来的文件
+func (a *HighWaterAllocator) save(mark int64) error {
+	return writeFileAtomic(a.Path, []byte(strconv.FormatInt(mark, 10)), 0644)
+}
+
+// snowflake ID 各部分的位数：41 位毫秒时间戳，10 位节点ID，12 位毫秒内的序号
+const (
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+
+	// MaxNodeID 节点ID的最大值
+	MaxNodeID = 1<<snowflakeNodeBits - 1
+)
+
+// SnowflakeEpoch snowflake ID 时间戳的起点
+var SnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeAllocator 分配 snowflake 风格的 64 位 ID，ID 中包含节点ID，不同节点之间不需要协调，重启之后也不会重复
+type SnowflakeAllocator struct {
+	Node int64 // 节点ID，每个服务器进程不同
+
+	last int64 // 上一次分配 ID 的毫秒时间戳
+	seq  int64 // 当前毫秒内的序号
+	lock sync.Mutex
+}
+
+// NewSnowflakeAllocator 创建节点 node 的 snowflake ID 分配器
+func NewSnowflakeAllocator(node int64) (*SnowflakeAllocator, error) {
+	if node < 0 || node > MaxNodeID {
+		return nil, fmt.Errorf("node id %d must be in [0, %d]", node, MaxNodeID)
+	}
+
+	return &SnowflakeAllocator{Node: node}, nil
+}
+
+// NextID 分配下一个 ID，同一毫秒内的序号用完之后等待下一毫秒；时钟回拨时沿用上一次的时间戳，保证 ID 递增
+func (a *SnowflakeAllocator) NextID() (int64, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := time.Since(SnowflakeEpoch).Milliseconds()
+	if now < a.last {
+		now = a.last
+	}
+
+	if now == a.last {
+		a.seq = (a.seq + 1) & (1<<snowflakeSeqBits - 1)
+		if a.seq == 0 {
+			// 当前毫秒内的序号已经用完
+			for now <= a.last {
+				time.Sleep(time.Millisecond)
+				now = time.Since(SnowflakeEpoch).Milliseconds()
+			}
+		}
+	} else {
+		a.seq = 0
+	}
+	a.last = now
+
+	return now<<(snowflakeNodeBits+snowflakeSeqBits) | a.Node<<snowflakeSeqBits | a.seq, nil
+}
+
+// NewPidAllocator 根据配置创建玩家实体ID（协议中的 Pid）的分配器，Pid 在协议中为 int32
+// 没有配置高水位文件时使用内存计数器
+func NewPidAllocator(conf IDConfig) (IDAllocator, error) {
+	if conf.PidFile == "" {
+		return &MemoryAllocator{}, nil
+	}
+
+	return NewHighWaterAllocator(conf.PidFile, int64(conf.PidStep), math.MaxInt32)
+}
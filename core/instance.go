@@ -0,0 +1,390 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// InstanceIDBase 副本场景ID的起始值，与配置中的静态场景ID区分开
+const InstanceIDBase int32 = 10000
+
+// MaxPartySize 一起进入副本的队伍最多有多少个玩家（包括队长）
+const MaxPartySize = 5
+
+// MaxInstancesPerLeader 一个玩家作为队长同时最多可以拥有多少个副本
+const MaxInstancesPerLeader = 1
+
+// Instance 由模板创建出来的副本
+type Instance struct {
+	// 副本的场景
+	Scene *Scene
+
+	// 副本使用的模板ID
+	TemplateID int32
+
+	// 副本从什么时候开始没有玩家，有玩家时为零值
+	emptySince time.Time
+
+	// 可以进入副本的队伍成员 map-key=角色ID，为空时只能由 GM 命令进入
+	party map[int64]bool
+
+	// 创建副本的队长的角色ID，由 GM 命令或者从快照恢复的副本为 0
+	leader int64
+
+	// 玩家进入副本之前所在的场景和坐标，离开副本时回到这里 map-key=角色ID
+	origins map[int64]instanceOrigin
+}
+
+// instanceOrigin 玩家进入副本之前所在的场景和坐标
+type instanceOrigin struct {
+	sceneID    int32
+	x, y, z, v float32
+}
+
+// InstanceManager 副本管理模块，按需根据模板创建副本，副本空闲超时之后销毁
+type InstanceManager struct {
+	// 副本所在的世界
+	world *WorldManager
+
+	// 副本模板 map-key=模板ID，value=场景配置
+	templates map[int32]*SceneConfig
+
+	// 当前存在的副本 map-key=副本场景ID，value=副本
+	instances map[int32]*Instance
+
+	// 副本场景ID生成器
+	idGen int32
+
+	// 副本没有玩家之后保留的时长
+	IdleTimeout time.Duration
+
+	// 通知清理协程退出的 channel
+	exitChan chan bool
+
+	// 保护副本集合的锁
+	lock sync.Mutex
+}
+
+// NewInstanceManager 根据世界的配置初始化副本管理模块
+func NewInstanceManager(world *WorldManager, conf *WorldConfig) *InstanceManager {
+	im := &InstanceManager{
+		world:       world,
+		templates:   make(map[int32]*SceneConfig),
+		instances:   make(map[int32]*Instance),
+		idGen:       InstanceIDBase,
+		IdleTimeout: time.Duration(conf.InstanceIdleTimeout) * time.Second,
+	}
+
+	for _, template := range conf.Instances {
+		im.templates[template.ID] = template
+	}
+
+	return im
+}
+
+// Start 启动清理空闲副本的协程
+func (im *InstanceManager) Start() {
+	im.exitChan = make(chan bool)
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				im.ReapIdle(now)
+			case <-im.exitChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止清理空闲副本的协程
+func (im *InstanceManager) Stop() {
+	if im.exitChan != nil {
+		close(im.exitChan)
+		im.exitChan = nil
+	}
+}
+
+// CreateInstance 根据模板复制一份场景配置，创建一个新的副本并注册到世界中
+func (im *InstanceManager) CreateInstance(templateID int32) (*Scene, error) {
+	return im.createInstance(templateID, 0, nil)
+}
+
+// createInstance 创建副本，leader 不为 0 时限制队长同时拥有的副本数量
+// 队长之前创建的副本已经没有玩家时直接销毁，不需要等待空闲超时
+func (im *InstanceManager) createInstance(templateID int32, leader int64, party map[int64]bool) (*Scene, error) {
+	template, ok := im.templates[templateID]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+
+	im.lock.Lock()
+	if leader != 0 {
+		owned := 0
+		for id, instance := range im.instances {
+			if instance.leader != leader {
+				continue
+			}
+			if instance.Scene.PlayerCount() == 0 {
+				im.destroyLocked(id, instance)
+				continue
+			}
+			owned++
+		}
+		if owned >= MaxInstancesPerLeader {
+			im.lock.Unlock()
+			return nil, ErrInstanceLimit
+		}
+	}
+	im.idGen++
+	scene := im.addInstance(im.idGen, template)
+	instance := im.instances[scene.ID]
+	instance.leader = leader
+	if party != nil {
+		instance.party = party
+	}
+	im.lock.Unlock()
+
+	im.world.AddScene(scene)
+	fmt.Printf("[Instance] create instance %s from template %d\n", scene.Name, templateID)
+
+	return scene, nil
+}
+
+// RestoreInstance 从快照恢复副本，副本使用快照中的场景ID，之后创建的副本ID都大于该ID
+func (im *InstanceManager) RestoreInstance(sceneID, templateID int32) (*Scene, error) {
+	template, ok := im.templates[templateID]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+	if sceneID <= InstanceIDBase {
+		return nil, fmt.Errorf("instance id %d must be greater than %d", sceneID, InstanceIDBase)
+	}
+
+	im.lock.Lock()
+	if _, ok := im.instances[sceneID]; ok {
+		im.lock.Unlock()
+		return nil, fmt.Errorf("instance %d already exists", sceneID)
+	}
+	if sceneID > im.idGen {
+		im.idGen = sceneID
+	}
+	scene := im.addInstance(sceneID, template)
+	im.lock.Unlock()
+
+	im.world.AddScene(scene)
+	fmt.Printf("[Instance] restore instance %s from template %d\n", scene.Name, templateID)
+
+	return scene, nil
+}
+
+// addInstance 复制模板的配置创建副本场景，调用者需要持有 lock
+func (im *InstanceManager) addInstance(id int32, template *SceneConfig) *Scene {
+	// 复制模板的配置，出生区域也需要复制一份
+	conf := *template
+	conf.ID = id
+	conf.Name = fmt.Sprintf("%s#%d", template.Name, id)
+	conf.Spawns = append([]SpawnConfig(nil), template.Spawns...)
+
+	scene := NewScene(&conf)
+	scene.TemplateID = template.ID
+	im.instances[id] = &Instance{
+		Scene:      scene,
+		TemplateID: template.ID,
+		emptySince: time.Now(),
+		party:      make(map[int64]bool),
+		origins:    make(map[int64]instanceOrigin),
+	}
+
+	return scene
+}
+
+// GetAllInstances 获取当前存在的全部副本
+func (im *InstanceManager) GetAllInstances() []*Instance {
+	im.lock.Lock()
+	defer im.lock.Unlock()
+
+	instances := make([]*Instance, 0, len(im.instances))
+	for _, instance := range im.instances {
+		instances = append(instances, instance)
+	}
+
+	return instances
+}
+
+// EnterInstance 将一组玩家传送到副本的出生区域中
+// 先校验全部玩家，任何一个玩家传送失败时，已经传送的玩家回到原来的场景和坐标，队伍不会分散在不同的场景中
+func (im *InstanceManager) EnterInstance(scene *Scene, players []*Player) error {
+	// 1.全部玩家都需要在世界中
+	for _, player := range players {
+		if im.world.GetPlayerByPid(player.Pid) != player {
+			return ErrPlayerNotFound
+		}
+	}
+
+	// 2.记录玩家原来的场景和坐标，用于传送失败时回滚
+	origins := make([]instanceOrigin, len(players))
+	for i, player := range players {
+		player.posLock.RLock()
+		origins[i] = instanceOrigin{player.SceneID, player.X, player.Y, player.Z, player.V}
+		player.posLock.RUnlock()
+	}
+
+	im.lock.Lock()
+	instance, ok := im.instances[scene.ID]
+	if ok {
+		// 玩家进入之前重置空闲时间，避免副本被清理
+		instance.emptySince = time.Time{}
+
+		// 记录离开副本时回到的位置，从其它副本进入时沿用在那个副本中记录的位置
+		for i, player := range players {
+			origin := origins[i]
+			if from, ok := im.instances[origin.sceneID]; ok {
+				if origin, ok = from.origins[player.RoleID]; !ok {
+					continue
+				}
+			}
+			instance.origins[player.RoleID] = origin
+		}
+	}
+	im.lock.Unlock()
+	if !ok {
+		return ErrSceneNotFound
+	}
+
+	// 3.依次传送全部玩家
+	for i, player := range players {
+		x, z := scene.SpawnPos()
+		if err := player.Teleport(scene.ID, x, 0, z, 0); err != nil {
+			for j, moved := range players[:i] {
+				o := origins[j]
+				if err := moved.Teleport(o.sceneID, o.x, o.y, o.z, o.v); err != nil {
+					fmt.Printf("[Instance] rollback player pid=%d error:%s\n", moved.Pid, err)
+				}
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreatePartyInstance 队长根据模板创建一个新的副本并进入，members 为队伍中其它玩家的ID
+// 队伍成员之后通过 JoinInstance 自己进入副本，不会被队长直接拉入副本
+func (im *InstanceManager) CreatePartyInstance(leader *Player, templateID int32, members []int32) (*Scene, error) {
+	// 1.校验队伍成员都在线
+	if len(members)+1 > MaxPartySize {
+		return nil, ErrPartyTooLarge
+	}
+	party := map[int64]bool{leader.RoleID: true}
+	for _, pid := range members {
+		member := im.world.GetPlayerByPid(pid)
+		if member == nil {
+			return nil, ErrPlayerNotFound
+		}
+		party[member.RoleID] = true
+	}
+
+	// 2.创建副本，记录可以进入副本的队伍成员
+	scene, err := im.createInstance(templateID, leader.RoleID, party)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3.队长进入副本
+	if err := im.EnterInstance(scene, []*Player{leader}); err != nil {
+		return nil, err
+	}
+
+	return scene, nil
+}
+
+// JoinInstance 队伍成员进入已经创建的副本，不是创建副本时的队伍成员不能进入
+func (im *InstanceManager) JoinInstance(player *Player, sceneID int32) error {
+	im.lock.Lock()
+	instance, ok := im.instances[sceneID]
+	allowed := ok && instance.party[player.RoleID]
+	im.lock.Unlock()
+	if !ok {
+		return ErrSceneNotFound
+	}
+	if !allowed {
+		return ErrNotPartyMember
+	}
+
+	return im.EnterInstance(instance.Scene, []*Player{player})
+}
+
+// LeaveInstance 玩家离开所在的副本，回到进入副本之前的场景和坐标
+// 没有记录进入之前的位置（例如从快照恢复的副本），或者原来的场景已经不存在时，回到默认场景的出生区域
+func (im *InstanceManager) LeaveInstance(player *Player) error {
+	im.lock.Lock()
+	instance, ok := im.instances[player.SceneID]
+	var origin instanceOrigin
+	var hasOrigin bool
+	if ok {
+		origin, hasOrigin = instance.origins[player.RoleID]
+		delete(instance.origins, player.RoleID)
+	}
+	im.lock.Unlock()
+	if !ok {
+		return ErrNotInInstance
+	}
+
+	if hasOrigin {
+		if err := player.Teleport(origin.sceneID, origin.x, origin.y, origin.z, origin.v); err == nil {
+			return nil
+		}
+	}
+
+	scene := im.world.DefaultScene()
+	x, z := scene.SpawnPos()
+
+	return player.Teleport(scene.ID, x, 0, z, 0)
+}
+
+// GetInstance 通过副本场景ID查询副本
+func (im *InstanceManager) GetInstance(sceneID int32) *Instance {
+	im.lock.Lock()
+	defer im.lock.Unlock()
+
+	return im.instances[sceneID]
+}
+
+// ReapIdle 销毁没有玩家的时间超过 IdleTimeout 的副本
+func (im *InstanceManager) ReapIdle(now time.Time) {
+	im.lock.Lock()
+	defer im.lock.Unlock()
+
+	for id, instance := range im.instances {
+		// 副本中还有玩家
+		if instance.Scene.PlayerCount() > 0 {
+			instance.emptySince = time.Time{}
+			continue
+		}
+
+		// 最后一个玩家刚刚离开
+		if instance.emptySince.IsZero() {
+			instance.emptySince = now
+			continue
+		}
+
+		if now.Sub(instance.emptySince) < im.IdleTimeout {
+			continue
+		}
+
+		im.destroyLocked(id, instance)
+	}
+}
+
+// destroyLocked 销毁没有玩家的副本，调用者需要持有 lock
+func (im *InstanceManager) destroyLocked(id int32, instance *Instance) {
+	im.world.RemoveScene(id)
+	delete(im.instances, id)
+	fmt.Printf("[Instance] destroy idle instance %s\n", instance.Scene.Name)
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInstanceManager(t *testing.T) {
+	conf, err := LoadWorldConfig("../conf/zinx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	world := NewWorldManager(conf)
+	im := world.InstanceMgr
+
+	if _, err := im.CreateInstance(1); err != ErrTemplateNotFound {
+		t.Errorf("err=%v, want ErrTemplateNotFound", err)
+	}
+
+	// 同一个模板创建两个互相独立的副本
+	cave1, err := im.CreateInstance(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cave2, _ := im.CreateInstance(100)
+	if cave1.ID == cave2.ID || !cave1.IsInstance() || world.GetScene(cave2.ID) == nil {
+		t.Fatalf("cave1=%d, cave2=%d", cave1.ID, cave2.ID)
+	}
+
+	// 一组玩家进入副本 1
+	party := []*Player{
+		newTestPlayer(t, world, nil, world.DefaultScene()),
+		newTestPlayer(t, world, nil, world.DefaultScene()),
+	}
+	for _, player := range party {
+		world.AddPlayer(player)
+	}
+	if err := im.EnterInstance(cave1, party); err != nil {
+		t.Fatal(err)
+	}
+	if cave1.PlayerCount() != 2 || world.DefaultScene().PlayerCount() != 0 {
+		t.Errorf("cave1=%d players", cave1.PlayerCount())
+	}
+
+	// 有玩家的副本不会被清理，空闲超时的副本被销毁
+	now := time.Now()
+	im.ReapIdle(now)
+	im.ReapIdle(now.Add(im.IdleTimeout))
+	if world.GetScene(cave1.ID) == nil {
+		t.Error("cave1 should not be destroyed")
+	}
+	if world.GetScene(cave2.ID) != nil || im.GetInstance(cave2.ID) != nil {
+		t.Error("cave2 should be destroyed")
+	}
+
+	// 最后一个玩家离开之后，超时才会销毁
+	for _, player := range party {
+		player.Offline()
+	}
+	im.ReapIdle(now)
+	if world.GetScene(cave1.ID) == nil {
+		t.Error("cave1 should wait for idle timeout")
+	}
+	im.ReapIdle(now.Add(im.IdleTimeout))
+	if world.GetScene(cave1.ID) != nil {
+		t.Error("cave1 should be destroyed")
+	}
+}
+
+func TestPartyInstance(t *testing.T) {
+	conf, err := LoadWorldConfig("../conf/zinx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	world := NewWorldManager(conf)
+	im := world.InstanceMgr
+
+	leader, _ := world.Login(&fakeConn{}, "leader", "")
+	member, _ := world.Login(&fakeConn{}, "member", "")
+	outsider, _ := world.Login(&fakeConn{}, "outsider", "")
+
+	// 队伍成员不在线或者队伍人数超过上限时不创建副本
+	if _, err := im.CreatePartyInstance(leader, 100, []int32{member.Pid, 99999}); err != ErrPlayerNotFound {
+		t.Errorf("err=%v, want ErrPlayerNotFound", err)
+	}
+	if _, err := im.CreatePartyInstance(leader, 100, make([]int32, MaxPartySize)); err != ErrPartyTooLarge {
+		t.Errorf("err=%v, want ErrPartyTooLarge", err)
+	}
+
+	// 队长创建副本之后进入，队伍成员自己进入，其它玩家不能进入
+	x, z := leader.X, leader.Z
+	cave, err := im.CreatePartyInstance(leader, 100, []int32{member.Pid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leader.SceneID != cave.ID || member.SceneID == cave.ID {
+		t.Fatalf("leader=%d, member=%d", leader.SceneID, member.SceneID)
+	}
+	if err := im.JoinInstance(outsider, cave.ID); err != ErrNotPartyMember {
+		t.Errorf("err=%v, want ErrNotPartyMember", err)
+	}
+	if err := im.JoinInstance(member, cave.ID); err != nil || member.SceneID != cave.ID {
+		t.Errorf("err=%v, scene=%d", err, member.SceneID)
+	}
+
+	// 有一个玩家不能进入时，整个队伍都不进入副本
+	stranger := newTestPlayer(t, world, nil, world.DefaultScene())
+	if err := im.EnterInstance(cave, []*Player{outsider, stranger}); err != ErrPlayerNotFound {
+		t.Errorf("err=%v, want ErrPlayerNotFound", err)
+	}
+	if outsider.SceneID != world.DefaultSceneID {
+		t.Errorf("outsider should stay in scene %d", world.DefaultSceneID)
+	}
+
+	// 队长的副本中还有玩家时不能再创建副本
+	if _, err := im.CreatePartyInstance(leader, 100, nil); err != ErrInstanceLimit {
+		t.Errorf("err=%v, want ErrInstanceLimit", err)
+	}
+	if err := im.LeaveInstance(outsider); err != ErrNotInInstance {
+		t.Errorf("err=%v, want ErrNotInInstance", err)
+	}
+
+	// 离开副本之后回到进入副本之前的位置
+	if err := im.LeaveInstance(member); err != nil || member.SceneID != world.DefaultSceneID {
+		t.Errorf("err=%v, scene=%d", err, member.SceneID)
+	}
+	if err := im.LeaveInstance(leader); err != nil || leader.SceneID != world.DefaultSceneID || cave.PlayerCount() != 0 {
+		t.Errorf("err=%v, scene=%d", err, leader.SceneID)
+	}
+	if leader.X != x || leader.Z != z {
+		t.Errorf("pos=(%f,%f), want (%f,%f)", leader.X, leader.Z, x, z)
+	}
+
+	// 副本中没有玩家之后，队长再次创建副本时销毁原来的副本
+	next, err := im.CreatePartyInstance(leader, 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if im.GetInstance(cave.ID) != nil || world.GetScene(cave.ID) != nil || im.GetInstance(next.ID) == nil {
+		t.Error("empty instance of the leader should be destroyed")
+	}
+}
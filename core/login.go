@@ -0,0 +1,156 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"szinx/pb"
+
+	"github.com/YungMonk/zinx/ziface"
+	"github.com/golang/protobuf/proto"
+)
+
+// WatchLogin 连接建立之后开始计时，超过 LoginTimeout 秒没有登录则断开连接
+func (wm *WorldManager) WatchLogin(conn ziface.IConnection) {
+	timeout := time.Duration(wm.Config.Auth.LoginTimeout) * time.Second
+
+	time.AfterFunc(timeout, func() {
+		if _, err := conn.GetProperty("pid"); err == nil {
+			return
+		}
+
+		fmt.Printf("====> Connection %d login timeout <====\n", conn.GetConnID())
+		if tcpConn := conn.GetTCPConnection(); tcpConn != nil {
+			tcpConn.Close()
+		}
+	})
+}
+
+// Login 校验账号的登录凭证，认证通过之后在默认场景中创建玩家，并将连接绑定到玩家
+// 账号的玩家正在等待恢复会话时，不创建新的玩家，直接将连接绑定到原来的玩家
+func (wm *WorldManager) Login(conn ziface.IConnection, account, token string) (*Player, error) {
+	// 1.一个连接只能登录一次
+	if _, err := conn.GetProperty("pid"); err == nil {
+		return nil, ErrAlreadyLoggedIn
+	}
+
+	// 服务器正在关闭时不再接受登录
+	if wm.IsClosing() {
+		return nil, ErrServerClosing
+	}
+
+	// 2.校验登录凭证
+	info, err := wm.Auth.Authenticate(account, token)
+	if err != nil {
+		return nil, err
+	}
+
+	wm.loginLock.Lock()
+	defer wm.loginLock.Unlock()
+
+	// 3.同一个账号同时只能有一个玩家在线
+	// 等待恢复会话的玩家（例如从快照恢复之后，客户端没有保存会话凭证）直接绑定到新的连接上
+	// 不校验凭证时任何人都可以使用这个账号登录，只能通过会话凭证恢复，不能接管
+	if existing := wm.GetPlayerByAccount(info.Account); existing != nil {
+		if _, insecure := wm.Auth.(*NoneAuthenticator); insecure || !existing.IsDetached() {
+			return nil, ErrAccountOnline
+		}
+		if err := existing.takeOver(conn, info); err != ErrSessionExpired {
+			return existing, err
+		}
+	}
+
+	// 4.在默认场景中创建一个Player对象，有保存的玩家数据时回到下线时的场景和坐标
+	player, err := NewPlayer(wm, conn, wm.DefaultScene())
+	if err != nil {
+		return nil, err
+	}
+	player.Account = info.Account
+	player.Name = info.Account
+	player.Level = info.Level
+	player.Session = newSessionToken()
+	if wm.Profiles != nil {
+		profile, err := wm.Profiles.Load(info.Account)
+		switch err {
+		case nil:
+			player.ApplyProfile(profile)
+		case ErrProfileNotFound:
+		default:
+			return nil, err
+		}
+	}
+
+	// 5.第一次登录的账号分配角色ID
+	if player.RoleID == 0 {
+		if player.RoleID, err = wm.RoleIDAlloc.NextID(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 6.告知客户端登录成功以及会话凭证，并同步当前的playerID给客户端 MsgID=1
+	SendLoginResult(conn, player, nil)
+	player.SyncPid()
+
+	// 7.给客户端发送MsgID=200的消息，同步当前player的位置给客户端
+	player.BroadCastStartPosition()
+
+	// 8.将当前连接绑定到一个Pid玩家ID的属性，需要在添加到世界之前绑定，连接断开的 Hook 才能找到玩家
+	conn.SetProperty("pid", player.Pid)
+
+	// 9.将新上线的玩家添加到世界管理模块（及所在场景）中，之前连接已经断开时玩家进入等待恢复会话的状态
+	wm.AddPlayer(player)
+	player.detachIfClosed(conn)
+
+	// 10.在当前玩家上线之后，触发同步当前玩家位置信息（告知周围玩家当前玩家已经上线）
+	player.SyncSurrounding()
+
+	// 11.发送世界和当前场景最近的聊天记录
+	player.SyncChatHistory()
+
+	return player, nil
+}
+
+// SendLoginResult 将登录的结果发送给连接，登录之前没有玩家对象，直接通过连接发送
+// 登录成功时 player 为登录的玩家，失败时为 nil
+func SendLoginResult(conn ziface.IConnection, player *Player, err error) {
+	// 组建 MsgID:213 的 proto 数据
+	protoMsg := &pb.LoginResult{
+		Code: LoginCode(err),
+	}
+	if player != nil {
+		protoMsg.Pid = player.Pid
+		protoMsg.Session = player.Session
+	}
+	if err != nil {
+		protoMsg.Msg = err.Error()
+	}
+
+	msg, err := proto.Marshal(protoMsg)
+	if err != nil {
+		fmt.Printf("marshal data error:%s\n", err)
+		return
+	}
+	if err := conn.SendMsg(213, msg); err != nil {
+		fmt.Printf("connection send msg error:%s\n", err)
+	}
+}
+
+// takeOver 重新登录的客户端接管等待恢复会话的玩家
+// 新的客户端没有之前的状态：移动序号重新从头开始，权限等级以本次认证的结果为准，并重新发送最近的聊天记录
+func (p *Player) takeOver(conn ziface.IConnection, info *AuthInfo) error {
+	p.lockPos()
+	p.LastMoveSeq = 0
+	p.unlockPos()
+
+	p.connLock.Lock()
+	p.Level = info.Level
+	p.connLock.Unlock()
+
+	if err := p.resume(conn); err != nil {
+		return err
+	}
+
+	p.SyncChatHistory()
+
+	return nil
+}
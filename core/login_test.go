@@ -0,0 +1,315 @@
+package core
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"szinx/pb"
+)
+
+func TestHMACAuthenticator(t *testing.T) {
+	a := &HMACAuthenticator{Secret: []byte("secret"), Level: PermGM}
+	token := a.Sign("alice", time.Now().Add(time.Minute))
+
+	if info, err := a.Authenticate("alice", token); err != nil || info.Account != "alice" || info.Level != PermGM {
+		t.Errorf("info=%v, err=%v", info, err)
+	}
+	if _, err := a.Authenticate("bob", token); err != ErrAuthFailed {
+		t.Errorf("err=%v, want ErrAuthFailed", err)
+	}
+	if _, err := (&HMACAuthenticator{Secret: []byte("other")}).Authenticate("alice", token); err != ErrAuthFailed {
+		t.Errorf("err=%v, want ErrAuthFailed", err)
+	}
+	if _, err := a.Authenticate("alice", a.Sign("alice", time.Now().Add(-time.Second))); err != ErrTokenExpired {
+		t.Errorf("err=%v, want ErrTokenExpired", err)
+	}
+}
+
+func TestFileAuthenticator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accounts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "accounts.json")
+	data := `[{"Account":"gm", "TokenHash":"` + HashToken("pass") + `", "Level":1}]`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewFileAuthenticator(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := a.Authenticate("gm", "pass"); err != nil || info.Level != PermGM {
+		t.Errorf("info=%v, err=%v", info, err)
+	}
+	if _, err := a.Authenticate("gm", "wrong"); err != ErrAuthFailed {
+		t.Errorf("err=%v, want ErrAuthFailed", err)
+	}
+	if _, err := a.Authenticate("nobody", "pass"); err != ErrAuthFailed {
+		t.Errorf("err=%v, want ErrAuthFailed", err)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	world.Auth = &HMACAuthenticator{Secret: []byte("secret")}
+	token := world.Auth.(*HMACAuthenticator).Sign("alice", time.Now().Add(time.Minute))
+
+	// 认证失败时不创建玩家
+	conn := &fakeConn{}
+	if _, err := world.Login(conn, "alice", "bad"); err != ErrAuthFailed || len(world.GetAllPlayers()) != 0 {
+		t.Errorf("err=%v, want ErrAuthFailed", err)
+	}
+
+	// 登录成功之后创建玩家，并将连接绑定到玩家
+	player, err := world.Login(conn, "alice", token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &pb.LoginResult{}
+	if !conn.last(213, result) || result.Code != LoginCodeOK || result.Pid != player.Pid || conn.count(1) != 1 {
+		t.Errorf("result=%v", result)
+	}
+	if pid, _ := conn.GetProperty("pid"); pid != player.Pid || world.GetPlayerByAccount("alice") != player {
+		t.Errorf("pid=%v", pid)
+	}
+
+	// 同一个连接不能重复登录，同一个账号不能同时在线
+	if _, err := world.Login(conn, "alice", token); err != ErrAlreadyLoggedIn {
+		t.Errorf("err=%v, want ErrAlreadyLoggedIn", err)
+	}
+	if _, err := world.Login(&fakeConn{}, "alice", token); err != ErrAccountOnline {
+		t.Errorf("err=%v, want ErrAccountOnline", err)
+	}
+
+	// 下线之后可以再次登录
+	player.Offline()
+	if _, err := world.Login(&fakeConn{}, "alice", token); err != nil {
+		t.Errorf("err=%v, want nil", err)
+	}
+}
+
+func TestPlayerResume(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	conn1 := &fakeConn{}
+	player, err := world.Login(conn1, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &pb.LoginResult{}
+	if !conn1.last(213, result) || result.Session == "" {
+		t.Fatalf("result=%v", result)
+	}
+
+	// 断线之后玩家保留在世界中
+	if !player.Detach(conn1) || world.GetPlayerByPid(player.Pid) != player {
+		t.Fatal("player should stay in the world")
+	}
+	if _, err := world.Resume(&fakeConn{}, "bad"); err != ErrSessionExpired {
+		t.Errorf("err=%v, want ErrSessionExpired", err)
+	}
+
+	// 恢复会话之后绑定新的连接，并收到完整的视野数据
+	conn2 := &fakeConn{}
+	if p, err := world.Resume(conn2, result.Session); err != nil || p != player || player.Conn != conn2 {
+		t.Fatalf("player=%v, err=%v", p, err)
+	}
+	resumed := &pb.LoginResult{}
+	if !conn2.last(213, resumed) || resumed.Pid != player.Pid || conn2.count(202) != 1 {
+		t.Errorf("result=%v, count=%d", resumed, conn2.count(202))
+	}
+
+	// 原来的连接断开不会影响新的连接
+	if player.Detach(conn1) || player.Conn != conn2 {
+		t.Error("stale connection should not detach player")
+	}
+
+	// 等待恢复超时之后下线，会话不能再恢复
+	player.Detach(conn2)
+	player.expireSession()
+	if world.GetPlayerByPid(player.Pid) != nil {
+		t.Error("player should be offline")
+	}
+	if _, err := world.Resume(&fakeConn{}, result.Session); err != ErrSessionExpired {
+		t.Errorf("err=%v, want ErrSessionExpired", err)
+	}
+}
+
+func TestLoginClosedConn(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+
+	// 连接断开的 Hook 在登录完成之前执行，玩家不会一直留在世界中
+	conn := &fakeConn{}
+	MarkConnClosed(conn)
+	player, err := world.Login(conn, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !player.IsDetached() || player.Conn != nil {
+		t.Error("player should be detached from the closed connection")
+	}
+}
+
+func TestLoginTakeOver(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	conn1 := &fakeConn{}
+	player, err := world.Login(conn1, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := player.Chat(&pb.Chat{Scope: ChatScopeWorld, Content: "hello"}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	player.LastMoveSeq = 10
+	player.Level = PermGM
+	player.Detach(conn1)
+
+	// 不校验凭证时不能接管等待恢复会话的玩家
+	if _, err := world.Login(&fakeConn{}, "alice", ""); err != ErrAccountOnline {
+		t.Errorf("err=%v, want ErrAccountOnline", err)
+	}
+
+	// 没有会话凭证的客户端重新登录之后接管原来的玩家，状态和新登录的玩家一致
+	auth := &HMACAuthenticator{Secret: []byte("secret")}
+	world.Auth = auth
+	conn2 := &fakeConn{}
+	if p, err := world.Login(conn2, "alice", auth.Sign("alice", time.Now().Add(time.Minute))); err != nil || p != player {
+		t.Fatalf("player=%v, err=%v", p, err)
+	}
+	if player.LastMoveSeq != 0 || player.Level != world.Config.DefaultPermission {
+		t.Errorf("seq=%d, level=%d", player.LastMoveSeq, player.Level)
+	}
+	history := &pb.ChatHistory{}
+	if conn2.count(211) != 2 || !conn2.last(211, history) || history.Scope != ChatScopeScene {
+		t.Errorf("count=%d, history=%v", conn2.count(211), history)
+	}
+	if err := player.HandleSeqMove(1, player.X+1, 0, player.Z, 0, time.Now().Add(time.Second)); err != nil {
+		t.Errorf("err=%v, first move after take over should be accepted", err)
+	}
+}
+
+func TestPlayerKick(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	server, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	world := NewWorldManager(DefaultWorldConfig())
+	conn := &tcpConn{tcp: server.(*net.TCPConn)}
+	player, err := world.Login(conn, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 踢下线之后 socket 被关闭，玩家已经不在世界中
+	player.Kick()
+	if _, err := ioutil.ReadAll(client); err != nil {
+		t.Errorf("read error:%s", err)
+	}
+	if world.GetPlayerByPid(player.Pid) != nil {
+		t.Fatal("player should be offline")
+	}
+
+	// 连接断开的 Hook 不会让玩家进入等待恢复会话的状态，不能使用原来的会话凭证回到世界中
+	if player.Detach(conn) {
+		t.Error("kicked player should not be detached")
+	}
+	if _, err := world.Resume(&fakeConn{}, player.Session); err != ErrSessionExpired {
+		t.Errorf("err=%v, want ErrSessionExpired", err)
+	}
+}
+
+// tcpConn 有 socket 的测试连接
+type tcpConn struct {
+	fakeConn
+	tcp *net.TCPConn
+}
+
+func (c *tcpConn) GetTCPConnection() *net.TCPConn {
+	return c.tcp
+}
+
+func TestHighWaterAllocator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "pid.hwm")
+
+	a, err := NewHighWaterAllocator(path, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for want := int64(1); want <= 2; want++ {
+		if id, err := a.NextID(); err != nil || id != want {
+			t.Errorf("id=%d, err=%v, want %d", id, err, want)
+		}
+	}
+
+	// 重启之后跳过上一次预留的 ID，不会与之前分配的 ID 重复
+	a, err = NewHighWaterAllocator(path, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for want := int64(4); want <= 5; want++ {
+		if id, err := a.NextID(); err != nil || id != want {
+			t.Errorf("id=%d, err=%v, want %d", id, err, want)
+		}
+	}
+	if _, err := a.NextID(); err != ErrIDExhausted {
+		t.Errorf("err=%v, want ErrIDExhausted", err)
+	}
+
+	// 目录不存在时自动创建，创建时就写入高水位
+	nested := filepath.Join(dir, "data", "ids", "pid.hwm")
+	if _, err := NewHighWaterAllocator(nested, 3, 5); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadFile(nested); err != nil || string(data) != "3" {
+		t.Errorf("mark=%q, err=%v", data, err)
+	}
+
+	// 文件路径不可写时创建失败
+	if _, err := NewHighWaterAllocator(filepath.Join(nested, "pid.hwm"), 3, 5); err == nil {
+		t.Error("allocator with invalid path should fail")
+	}
+}
+
+func TestSnowflakeAllocator(t *testing.T) {
+	a, err := NewSnowflakeAllocator(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ID 递增，并且包含节点ID
+	last := int64(0)
+	for i := 0; i < 10000; i++ {
+		id, err := a.NextID()
+		if err != nil || id <= last || id>>snowflakeSeqBits&MaxNodeID != 7 {
+			t.Fatalf("id=%d, last=%d, err=%v", id, last, err)
+		}
+		last = id
+	}
+
+	if _, err := NewSnowflakeAllocator(MaxNodeID + 1); err == nil {
+		t.Error("node id should be out of range")
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"szinx/pb"
+)
+
+// 移动校验的参数
+const (
+	MoveSpeedTolerance float32 = 1.2                    // 允许超出最大速度的比例，容忍网络抖动
+	MoveMaxInterval            = 500 * time.Millisecond // 两次移动之间最多累计的时间，避免长时间静止后瞬移
+)
+
+// CheckMove 校验客户端上报的移动坐标，返回服务器认可的坐标
+// 坐标不合法或者移动速度过快时返回 ErrInvalidPosition/ErrMoveTooFast，玩家应该保持原地不动；
+// 坐标超出地图边界时返回 ErrOutOfBounds，同时返回限制在地图边界内的坐标
+func (p *Player) CheckMove(x, y, z, v float32, now time.Time) (float32, float32, float32, error) {
+	p.posLock.RLock()
+	px, py, pz, sceneID, lastMoveTime := p.X, p.Y, p.Z, p.SceneID, p.lastMoveTime
+	p.posLock.RUnlock()
+
+	// 1.拒绝 NaN/Inf 坐标
+	for _, f := range []float32{x, y, z, v} {
+		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+			return px, py, pz, ErrInvalidPosition
+		}
+	}
+
+	// 2.根据距离上一次移动的时间校验平面上的移动距离
+	elapsed := now.Sub(lastMoveTime)
+	if elapsed > MoveMaxInterval {
+		elapsed = MoveMaxInterval
+	}
+	maxDist := p.Speed * float32(elapsed.Seconds()) * MoveSpeedTolerance
+	dx, dz := x-px, z-pz
+	if dx*dx+dz*dz > maxDist*maxDist {
+		return px, py, pz, ErrMoveTooFast
+	}
+
+	// 3.将坐标限制在地图边界内
+	minX, maxX, minY, maxY := p.world.GetScene(sceneID).AoiManager.GetBounds()
+	cx, cz := clampAxis(x, minX, maxX), clampAxis(z, minY, maxY)
+	if cx != x || cz != z {
+		return cx, y, cz, ErrOutOfBounds
+	}
+
+	return x, y, z, nil
+}
+
+// HandleMove 校验并执行客户端上报的移动
+// 坐标不合法或者移动速度过快时玩家保持原地不动；坐标超出地图边界时移动到边界内，这两种情况都会返回错误
+func (p *Player) HandleMove(x, y, z, v float32, now time.Time) error {
+	x, y, z, err := p.CheckMove(x, y, z, v, now)
+	if err == ErrInvalidPosition || err == ErrMoveTooFast {
+		return err
+	}
+
+	p.UpdatePos(x, y, z, v)
+
+	return err
+}
+
+// HandleSeqMove 校验并执行带序号的移动，然后将处理结果确认给客户端
+// 序号不大于已经处理过的序号时认为是重复的输入，直接丢弃
+func (p *Player) HandleSeqMove(seq uint32, x, y, z, v float32, now time.Time) error {
+	p.lockPos()
+	if seq <= p.LastMoveSeq {
+		p.unlockPos()
+		return ErrStaleMoveSeq
+	}
+	p.LastMoveSeq = seq
+	p.unlockPos()
+
+	err := p.HandleMove(x, y, z, v, now)
+	p.SendMoveAck(err)
+
+	return err
+}
+
+// SendMoveAck 将最后处理的移动序号和服务器认可的坐标发送给客户端
+func (p *Player) SendMoveAck(err error) {
+	// 组建 MsgID:207 的 proto 数据
+	p.posLock.RLock()
+	protoMsg := &pb.MoveAck{
+		Seq: p.LastMoveSeq,
+		P: &pb.Position{
+			X: p.X,
+			Y: p.Y,
+			Z: p.Z,
+			V: p.V,
+		},
+	}
+	p.posLock.RUnlock()
+	if err != nil {
+		protoMsg.Code = MoveCode(err)
+	}
+
+	p.SendMsg(207, protoMsg)
+}
+
+// clampAxis 将坐标限制在 [min, max) 范围内
+func clampAxis(f float32, min, max int) float32 {
+	if f < float32(min) {
+		return float32(min)
+	}
+	if f >= float32(max) {
+		return math.Nextafter32(float32(max), float32(min))
+	}
+	return f
+}
+
+// SendMoveCorrection 将服务器认可的坐标发送给客户端，纠正客户端的位置
+func (p *Player) SendMoveCorrection(err error) {
+	// 组建 MsgID:204 的 proto 数据
+	p.posLock.RLock()
+	protoMsg := &pb.MoveCorrection{
+		Code: MoveCode(err),
+		Msg:  err.Error(),
+		P: &pb.Position{
+			X: p.X,
+			Y: p.Y,
+			Z: p.Z,
+			V: p.V,
+		},
+	}
+	p.posLock.RUnlock()
+
+	p.SendMsg(204, protoMsg)
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"szinx/pb"
+)
+
+func TestPlayerCheckMove(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	player := newTestPlayer(t, world, nil, world.DefaultScene())
+	world.AddPlayer(player)
+	player.X, player.Z = 200, 200
+	now := player.lastMoveTime.Add(100 * time.Millisecond)
+
+	// NaN/Inf 坐标
+	if _, _, _, err := player.CheckMove(float32(math.NaN()), 0, 200, 0, now); err != ErrInvalidPosition {
+		t.Errorf("err=%v, want ErrInvalidPosition", err)
+	}
+	if _, _, _, err := player.CheckMove(200, 0, 200, float32(math.Inf(1)), now); err != ErrInvalidPosition {
+		t.Errorf("err=%v, want ErrInvalidPosition", err)
+	}
+
+	// 100ms 内最多移动 20*0.1*1.2=2.4
+	if _, _, _, err := player.CheckMove(202, 0, 200, 0, now); err != nil {
+		t.Errorf("err=%v, want nil", err)
+	}
+	if x, _, _, err := player.CheckMove(203, 0, 200, 0, now); err != ErrMoveTooFast || x != 200 {
+		t.Errorf("x=%f, err=%v, want ErrMoveTooFast", x, err)
+	}
+
+	// 长时间静止之后也不能瞬移
+	if _, _, _, err := player.CheckMove(250, 0, 200, 0, now.Add(time.Hour)); err != ErrMoveTooFast {
+		t.Errorf("err=%v, want ErrMoveTooFast", err)
+	}
+
+	// 超出地图边界时限制在边界内
+	player.X, player.Z = 414, 200
+	x, _, _, err := player.CheckMove(415.5, 0, 200, 0, now)
+	if err != ErrOutOfBounds || x >= 415 || x < 414 {
+		t.Errorf("x=%f, err=%v, want ErrOutOfBounds", x, err)
+	}
+}
+
+func TestPlayerHandleSeqMove(t *testing.T) {
+	world := NewWorldManager(DefaultWorldConfig())
+	conn := &fakeConn{}
+	player := newTestPlayer(t, world, conn, world.DefaultScene())
+	world.AddPlayer(player)
+	x, z := player.X, player.Z
+	now := player.lastMoveTime.Add(100 * time.Millisecond)
+
+	// 合法的移动确认序号和新的坐标
+	if err := player.HandleSeqMove(1, x+1, 0, z, 0, now); err != nil {
+		t.Fatal(err)
+	}
+	ack := &pb.MoveAck{}
+	if !conn.last(207, ack) || ack.Seq != 1 || ack.Code != 0 || ack.P.X != x+1 {
+		t.Errorf("ack=%v", ack)
+	}
+
+	// 重复或者过期的序号直接丢弃，不回复确认
+	if err := player.HandleSeqMove(1, x+2, 0, z, 0, now); err != ErrStaleMoveSeq {
+		t.Errorf("err=%v, want ErrStaleMoveSeq", err)
+	}
+	if conn.count(207) != 1 || player.X != x+1 {
+		t.Errorf("count=%d, x=%f", conn.count(207), player.X)
+	}
+
+	// 被拒绝的移动也确认序号，并带上错误码和服务器认可的坐标
+	now = now.Add(100 * time.Millisecond)
+	if err := player.HandleSeqMove(3, x+50, 0, z, 0, now); err != ErrMoveTooFast {
+		t.Errorf("err=%v, want ErrMoveTooFast", err)
+	}
+	ack = &pb.MoveAck{}
+	if !conn.last(207, ack) || ack.Seq != 3 || ack.Code != MoveCodeTooFast || ack.P.X != x+1 {
+		t.Errorf("ack=%v", ack)
+	}
+}
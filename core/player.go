@@ -2,8 +2,9 @@ package core
 
 import (
 	"fmt"
-	"math/rand"
+	"math"
 	"sync"
+	"time"
 
 	"szinx/pb"
 
@@ -13,35 +14,80 @@ import (
 
 // Player 玩家对象
 type Player struct {
-	Pid  int32              // 玩家 id
-	Conn ziface.IConnection // 当前玩家的连接（用于和客户端的连接）
-	X    float32            // 平面的 x 坐标
-	Y    float32            // 高度
-	Z    float32            // 平面的 y 坐标
-	V    float32            // 玩家的旋转的角度（0-360）
-}
-
-// PIDGen PlayerID 生成器
-var PIDGen int32 = 1 // 用来生成玩家 id 的计数器
-// IDLock 保护 PIDGen 的 Mutex
-var IDLock sync.Mutex
-
-// NewPlayer 创建一个玩家的方法
-func NewPlayer(conn ziface.IConnection) *Player {
-	// 生成一个玩家 ID
-	IDLock.Lock()
-	id := PIDGen
-	PIDGen++
-	IDLock.Unlock()
+	Pid     int32              // 玩家实体 id，每次上线重新分配，用于协议
+	RoleID  int64              // 角色 id，随玩家数据一起保存，不会改变
+	Account string             // 玩家登录的账号
+	Session string             // 登录时下发的会话凭证
+	Name    string             // 角色名称
+	Conn    ziface.IConnection // 当前玩家的连接（用于和客户端的连接）
+	SceneID int32              // 玩家当前所在的场景 id
+	X       float32            // 平面的 x 坐标
+	Y       float32            // 高度
+	Z       float32            // 平面的 y 坐标
+	V       float32            // 玩家的旋转的角度（0-360）
+	Speed   float32            // 玩家每秒最多移动的距离
+	Level   int32              // 玩家的权限等级，决定可以执行哪些命令，上线之后由 connLock 保护
 
-	return &Player{
-		Pid:  id,
-		Conn: conn,
-		X:    float32(160 + rand.Intn(10)), // 随机在160坐标点，基于平面x轴若干偏移
-		Y:    0,
-		Z:    float32(140 + rand.Intn(20)), // 随机在140坐标点，基于平面y轴若干偏移
-		V:    0,                            // 角度为0
+	LastMoveSeq  uint32    // 最后处理的客户端移动序号
+	lastMoveTime time.Time // 上一次移动（或出生、传送）的时间，用于校验移动速度
+
+	world *WorldManager // 玩家所在的世界
+
+	deltaBases map[int32]*deltaBase // 同步给客户端的视野内玩家的坐标基准，用于发送坐标增量
+	deltaLock  sync.Mutex           // 保护 deltaBases 的锁
+
+	actionReady map[int32]time.Time // 动作冷却结束的时间 map-key=动作ID
+	actionLock  sync.Mutex          // 保护 actionReady 的锁
+
+	chat     chatLimiter // 聊天的频率限制和禁言状态
+	chatLock sync.Mutex  // 保护 chat 的锁
+
+	detached    bool         // 连接已经断开，等待恢复会话
+	offline     bool         // 玩家已经下线，会话不能再恢复
+	detachTimer *time.Timer  // 等待恢复会话超时之后让玩家下线的定时器
+	connLock    sync.RWMutex // 保护 Conn、权限等级以及会话状态的锁
+
+	attrs    map[string]int64 // 角色的属性，随玩家数据一起保存
+	attrLock sync.RWMutex     // 保护 attrs 的锁
+
+	posLock sync.RWMutex // 保护所在场景、坐标、LastMoveSeq 以及 lastMoveTime 的锁
+
+	saveLock sync.Mutex // 保证同一个玩家的数据按生成的顺序保存
+}
+
+// NewPlayer 创建一个玩家的方法，玩家出生在世界的指定场景中
+func NewPlayer(world *WorldManager, conn ziface.IConnection, scene *Scene) (*Player, error) {
+	// 分配一个玩家实体 ID
+	id, err := world.PidAlloc.NextID()
+	if err != nil {
+		return nil, err
+	}
+	if id <= 0 || id > math.MaxInt32 {
+		return nil, ErrIDExhausted
 	}
+
+	// 在场景的出生区域中随机一个坐标
+	x, z := scene.SpawnPos()
+
+	return &Player{
+		Pid:     int32(id),
+		Conn:    conn,
+		SceneID: scene.ID,
+		X:       x, // 出生点基于平面x轴若干偏移
+		Y:       0,
+		Z:       z, // 出生点基于平面y轴若干偏移
+		V:       0, // 角度为0
+		Speed:   world.Config.MoveSpeed,
+		Level:   world.Config.DefaultPermission,
+
+		lastMoveTime: time.Now(),
+		world:        world,
+	}, nil
+}
+
+// Scene 获取玩家当前所在的场景
+func (p *Player) Scene() *Scene {
+	return p.world.GetScene(p.SceneID)
 }
 
 // SendMsg 提供一个发送给客户端消息的方法
@@ -55,11 +101,17 @@ func (p *Player) SendMsg(msgID uint32, data proto.Message) {
 	}
 
 	// 将转化后的二进制文件通过zinx框架的SendMsg方法发送给客户端
-	if p.Conn == nil {
-		fmt.Printf("connection in player is nil\n")
+	p.connLock.RLock()
+	conn, detached := p.Conn, p.detached
+	p.connLock.RUnlock()
+	if conn == nil {
+		// 等待恢复会话的玩家没有连接，消息直接丢弃
+		if !detached {
+			fmt.Printf("connection in player is nil\n")
+		}
 		return
 	}
-	if err := p.Conn.SendMsg(msgID, msg); err != nil {
+	if err := conn.SendMsg(msgID, msg); err != nil {
 		fmt.Printf("player send msg error:%s", err)
 		return
 	}
@@ -98,8 +150,15 @@ func (p *Player) BroadCastStartPosition() {
 	p.SendMsg(200, protoMsg)
 }
 
-// Talk 玩家广播聊天消息到世界
+// Talk 玩家广播聊天消息到当前场景
 func (p *Player) Talk(content string) {
+	// 保存到当前场景的聊天记录中
+	p.recordChat(&pb.Chat{
+		Pid:     p.Pid,
+		Scope:   ChatScopeScene,
+		Content: content,
+	})
+
 	// 组建 MsgID:200 的 proto 数据
 	protoMsg := &pb.BroadCast{
 		Pid: p.Pid,
@@ -109,10 +168,10 @@ func (p *Player) Talk(content string) {
 		},
 	}
 
-	// 得到当前所有在线的玩家
-	players := WorldMgrObj.GetAllPlayers()
+	// 得到当前场景中所有的玩家
+	players := p.Scene().GetAllPlayers()
 
-	// 向所有玩家（包括自己）发送 MsgID:200 消息
+	// 向场景中所有玩家（包括自己）发送 MsgID:200 消息
 	for _, player := range players {
 		// player 分别给对应的客户端发送消息
 		player.SendMsg(200, protoMsg)
@@ -166,13 +225,41 @@ func (p *Player) SyncSurrounding() {
 	p.SendMsg(202, syncProtoMsg)
 }
 
+// lockPos 修改玩家的场景和坐标之前加锁，世界正在生成快照时等待快照完成
+func (p *Player) lockPos() {
+	p.world.snapshotLock.RLock()
+	p.posLock.Lock()
+}
+
+// unlockPos 修改玩家的场景和坐标之后解锁
+func (p *Player) unlockPos() {
+	p.posLock.Unlock()
+	p.world.snapshotLock.RUnlock()
+}
+
 // UpdatePos 更新当前玩家的坐标（广播玩家当前位置的移动信息）
 func (p *Player) UpdatePos(x, y, z, v float32) {
-	IDLock.Lock()
+	// 1.更新玩家坐标
+	p.lockPos()
 	p.X, p.Y, p.Z, p.V = x, y, z, v
-	IDLock.Unlock()
+	p.lastMoveTime = time.Now()
+	p.unlockPos()
+
+	// 2.更新玩家在 AOI 中的位置，得到离开视野和进入视野的玩家
+	leavePids, enterPids := p.Scene().AoiManager.Move(int(p.Pid), x, z)
+
+	// 3.处理视野的变化
+	if len(leavePids) > 0 || len(enterPids) > 0 {
+		p.OnExchangeAoi(p.getPlayersByPids(leavePids), p.getPlayersByPids(enterPids))
+	}
+
+	// 4.开启了帧循环时，只标记玩家的坐标有变化，由帧循环合并之后统一广播
+	if p.world.Config.TickRate > 0 {
+		p.Scene().MarkDirty(p)
+		return
+	}
 
-	// 给其它玩家广播当前玩家位置变动信息
+	// 5.给其它玩家广播当前玩家位置变动信息
 	broadcastProtoMsg := &pb.BroadCast{
 		Pid: p.Pid,
 		Tp:  4,
@@ -195,19 +282,193 @@ func (p *Player) UpdatePos(x, y, z, v float32) {
 	}
 }
 
-// GetSurroundingPlayers 获取当前玩家周围（九宫格内）的玩家信息
+// OnExchangeAoi 玩家视野变化之后，处理离开视野和进入视野的玩家
+func (p *Player) OnExchangeAoi(leavePlayers, enterPlayers []*Player) {
+	// 1.处理离开视野的玩家
+	// 1.1 组建 MsgID:201 的 proto 数据（告知对方当前玩家已经消失）
+	offlineProtoMsg := &pb.SyncPid{
+		Pid: p.Pid,
+	}
+	for _, player := range leavePlayers {
+		// 1.2 让离开视野的玩家看不到当前玩家
+		player.SendMsg(201, offlineProtoMsg)
+		player.forgetDeltaBase(p.Pid)
+		p.forgetDeltaBase(player.Pid)
+
+		// 1.3 让当前玩家看不到离开视野的玩家
+		p.SendMsg(201, &pb.SyncPid{
+			Pid: player.Pid,
+		})
+	}
+
+	// 2.处理进入视野的玩家
+	// 2.1 组建 MsgID:200 的 proto 数据（让对方看到当前玩家）
+	if len(enterPlayers) == 0 {
+		return
+	}
+	onlineProtoMsg := &pb.BroadCast{
+		Pid: p.Pid,
+		Tp:  2,
+		Data: &pb.BroadCast_P{
+			P: &pb.Position{
+				X: p.X,
+				Y: p.Y,
+				Z: p.Z,
+				V: p.V,
+			},
+		},
+	}
+
+	// 2.2 组建 MsgID:202 的 proto 数据（让当前玩家看到进入视野的玩家）
+	playersProtoMsg := make([]*pb.Player, 0, len(enterPlayers))
+	for _, player := range enterPlayers {
+		player.SendMsg(200, onlineProtoMsg)
+
+		playersProtoMsg = append(playersProtoMsg, &pb.Player{
+			Pid: player.Pid,
+			P: &pb.Position{
+				X: player.X,
+				Y: player.Y,
+				Z: player.Z,
+				V: player.V,
+			},
+		})
+	}
+	p.SendMsg(202, &pb.SyncPlayer{
+		Ps: playersProtoMsg,
+	})
+}
+
+// getPlayersByPids 根据一组 playerIDs 获取当前场景中除自己以外的玩家
+func (p *Player) getPlayersByPids(pids []int) []*Player {
+	scene := p.Scene()
+	players := make([]*Player, 0, len(pids))
+	for _, pid := range pids {
+		if int32(pid) == p.Pid {
+			continue
+		}
+		if player := scene.GetPlayerByPid(int32(pid)); player != nil {
+			players = append(players, player)
+		}
+	}
+
+	return players
+}
+
+// GetSurroundingPlayers 获取当前玩家视野内（九宫格或半径内）的玩家信息
 func (p *Player) GetSurroundingPlayers() []*Player {
-	pids := WorldMgrObj.AoiManager.GetPidsByPos(p.X, p.Z)
+	scene := p.Scene()
+	pids := scene.AoiManager.GetPidsByPos(p.X, p.Z)
 	players := make([]*Player, 0, len(pids))
 	for _, pid := range pids {
-		players = append(players, WorldMgrObj.GetPlayerByPid(int32(pid)))
+		if player := scene.GetPlayerByPid(int32(pid)); player != nil {
+			players = append(players, player)
+		}
 	}
 
 	return players
 }
 
+// Teleport 将玩家传送到指定场景的坐标，sceneID 为 0 时表示在当前场景中传送
+// 校验失败时返回错误，玩家保持原地不动
+func (p *Player) Teleport(sceneID int32, x, y, z, v float32) error {
+	// 1.校验目标场景及坐标
+	if sceneID == 0 {
+		sceneID = p.SceneID
+	}
+	target := p.world.GetScene(sceneID)
+	if target == nil {
+		return ErrSceneNotFound
+	}
+	if !InAOIBounds(target.AoiManager, x, z) {
+		return ErrOutOfBounds
+	}
+
+	// 2.让原来视野内的玩家看不到当前玩家，同时让当前玩家看不到他们
+	offlineProtoMsg := &pb.SyncPid{
+		Pid: p.Pid,
+	}
+	for _, player := range p.GetSurroundingPlayers() {
+		if player.Pid == p.Pid {
+			continue
+		}
+		player.SendMsg(201, offlineProtoMsg)
+		player.forgetDeltaBase(p.Pid)
+		p.SendMsg(201, &pb.SyncPid{
+			Pid: player.Pid,
+		})
+	}
+	p.clearDeltaBases()
+
+	// 3.将玩家从原来的场景中移除，放到目标场景的坐标中
+	p.Scene().RemovePlayerByPid(p.Pid)
+
+	p.lockPos()
+	p.SceneID = sceneID
+	p.X, p.Y, p.Z, p.V = x, y, z, v
+	p.lastMoveTime = time.Now()
+	p.unlockPos()
+
+	target.AddPlayer(p)
+
+	// 4.告知客户端传送成功，并同步新的视野
+	p.SendTeleportResult(nil)
+	p.SyncSurrounding()
+
+	return nil
+}
+
+// SendTeleportResult 将传送的结果以及玩家当前的场景和坐标发送给客户端
+func (p *Player) SendTeleportResult(err error) {
+	// 组建 MsgID:203 的 proto 数据
+	protoMsg := &pb.TeleportResult{
+		Code:    TeleportCode(err),
+		SceneID: p.SceneID,
+		P: &pb.Position{
+			X: p.X,
+			Y: p.Y,
+			Z: p.Z,
+			V: p.V,
+		},
+	}
+	if err != nil {
+		protoMsg.Msg = err.Error()
+	}
+
+	p.SendMsg(203, protoMsg)
+}
+
+// Kick 将玩家踢下线
+// 玩家先下线再关闭 socket 连接，被踢的玩家不会进入等待恢复会话的状态，不能使用原来的会话凭证回到世界中
+func (p *Player) Kick() {
+	p.Disconnect()
+}
+
+// Disconnect 让玩家立即下线，再关闭玩家的 socket 连接
+// 连接断开的 Hook 执行时玩家已经不在世界中，不会再保留玩家等待恢复会话
+func (p *Player) Disconnect() {
+	p.connLock.RLock()
+	conn := p.Conn
+	p.connLock.RUnlock()
+
+	p.Offline()
+
+	if conn != nil && conn.GetTCPConnection() != nil {
+		conn.GetTCPConnection().Close()
+	}
+}
+
 // Offline 玩家下线
 func (p *Player) Offline() {
+	// 会话不能再恢复
+	p.connLock.Lock()
+	p.offline = true
+	if p.detachTimer != nil {
+		p.detachTimer.Stop()
+		p.detachTimer = nil
+	}
+	p.connLock.Unlock()
+
 	// 获取当前玩家周边九宫格内的玩家信息
 	players := p.GetSurroundingPlayers()
 
@@ -218,11 +479,14 @@ func (p *Player) Offline() {
 
 	for _, player := range players {
 		player.SendMsg(201, protoMsg)
+		player.forgetDeltaBase(p.Pid)
 	}
 
-	// 将当前玩家从AOI管理器删除
-	WorldMgrObj.AoiManager.RemovePidFromGridByPos(int(p.Pid), p.X, p.Z)
+	// 保存玩家的数据
+	if err := p.SaveProfile(); err != nil {
+		fmt.Printf("save profile of player pid=%d error:%s\n", p.Pid, err)
+	}
 
-	// 将当前玩家从世界管理器删除
-	WorldMgrObj.RemovePlayerByPid(p.Pid)
+	// 将当前玩家从世界管理器（包括所在场景的AOI管理器）删除
+	p.world.RemovePlayerByPid(p.Pid)
 }
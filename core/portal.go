@@ -0,0 +1,41 @@
+package core
+
+// UsePortal 使用玩家所在场景中的传送门，由服务器在目标场景的出生区域中选择目的地
+// 传送门不在玩家所在的场景中，或者玩家距离传送门超过传送门的半径时拒绝传送
+func (p *Player) UsePortal(portalID int32) error {
+	portal, ok := p.world.Portals[portalID]
+	if !ok || portal.SceneID != p.SceneID {
+		return ErrPortalNotFound
+	}
+
+	p.posLock.RLock()
+	dx, dz := p.X-portal.X, p.Z-portal.Z
+	p.posLock.RUnlock()
+	if dx*dx+dz*dz > portal.Radius*portal.Radius {
+		return ErrPortalTooFar
+	}
+
+	target := p.world.GetScene(portal.Target)
+	if target == nil {
+		return ErrSceneNotFound
+	}
+	x, z := target.SpawnPos()
+
+	return p.Teleport(target.ID, x, 0, z, 0)
+}
+
+// RequestTeleport 客户端请求传送到指定场景的坐标，权限等级不低于 TeleportPermission 才可以传送
+// 副本只能通过副本管理模块进入，不能直接传送到其它副本中
+func (p *Player) RequestTeleport(sceneID int32, x, y, z, v float32) error {
+	if p.PermLevel() < p.world.Config.TeleportPermission {
+		return ErrPermissionDenied
+	}
+
+	if sceneID != 0 && sceneID != p.SceneID {
+		if scene := p.world.GetScene(sceneID); scene != nil && scene.IsInstance() {
+			return ErrSceneForbidden
+		}
+	}
+
+	return p.Teleport(sceneID, x, y, z, v)
+}
@@ -0,0 +1,257 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Profile 玩家的持久化数据，按账号保存
+type Profile struct {
+	Account string           // 账号
+	RoleID  int64            // 角色ID
+	Name    string           // 角色名称
+	SceneID int32            // 下线时所在的场景ID
+	X       float32          // 平面的 x 坐标
+	Y       float32          // 高度
+	Z       float32          // 平面的 y 坐标
+	V       float32          // 旋转的角度
+	Attrs   map[string]int64 // 角色的属性
+	SavedAt time.Time        // 最后保存的时间
+}
+
+// PlayerRepository 玩家数据的存储
+type PlayerRepository interface {
+	// Load 加载账号的玩家数据，账号没有保存过数据时返回 ErrProfileNotFound
+	Load(account string) (*Profile, error)
+
+	// Save 保存玩家数据
+	Save(profile *Profile) error
+}
+
+// FileRepository 将每个账号的玩家数据保存为目录中的一个 JSON 文件
+type FileRepository struct {
+	// 保存玩家数据的目录
+	Dir string
+
+	// 保证同一时间只有一个协程写文件
+	lock sync.Mutex
+}
+
+// NewFileRepository 创建文件存储，目录不存在时自动创建
+func NewFileRepository(dir string) (*FileRepository, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &FileRepository{Dir: dir}, nil
+}
+
+// path 账号对应的文件路径，账号经过十六进制编码，避免账号中的特殊字符
+func (r *FileRepository) path(account string) string {
+	return filepath.Join(r.Dir, hex.EncodeToString([]byte(account))+".json")
+}
+
+// Load 从文件中加载账号的玩家数据
+func (r *FileRepository) Load(account string) (*Profile, error) {
+	data, err := ioutil.ReadFile(r.path(account))
+	if os.IsNotExist(err) {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &Profile{}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("parse profile of %q error: %s", account, err)
+	}
+
+	return profile, nil
+}
+
+// Save 将玩家数据写入临时文件之后再替换原来的文件，避免写入一半时进程退出导致数据损坏
+func (r *FileRepository) Save(profile *Profile) error {
+	if profile.Account == "" {
+		return errors.New("profile has no account")
+	}
+
+	data, err := json.MarshalIndent(profile, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return writeFileAtomic(r.path(profile.Account), data, 0644)
+}
+
+// writeFileAtomic 将数据写入临时文件之后再替换原来的文件，避免写入一半时进程退出导致文件损坏，目录不存在时自动创建
+// 临时文件在替换之前同步到磁盘，系统崩溃之后不会出现替换成功但内容为空的文件
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Profile 生成玩家当前的持久化数据
+func (p *Player) Profile() *Profile {
+	p.posLock.RLock()
+	profile := p.profileLocked()
+	p.posLock.RUnlock()
+
+	profile.Attrs = p.copyAttrs()
+
+	return profile
+}
+
+// profileLocked 生成不包含属性的持久化数据，调用者需要持有玩家的 posLock 或者世界的 snapshotLock
+func (p *Player) profileLocked() *Profile {
+	return &Profile{
+		Account: p.Account,
+		RoleID:  p.RoleID,
+		Name:    p.Name,
+		SceneID: p.SceneID,
+		X:       p.X,
+		Y:       p.Y,
+		Z:       p.Z,
+		V:       p.V,
+		SavedAt: time.Now(),
+	}
+}
+
+// copyAttrs 复制一份玩家的属性
+func (p *Player) copyAttrs() map[string]int64 {
+	p.attrLock.RLock()
+	defer p.attrLock.RUnlock()
+
+	attrs := make(map[string]int64, len(p.attrs))
+	for k, v := range p.attrs {
+		attrs[k] = v
+	}
+
+	return attrs
+}
+
+// ApplyProfile 使用持久化数据恢复玩家的名称、属性以及下线时的场景和坐标
+// 原来的场景已经不存在、是副本或者坐标超出地图边界时，玩家留在出生的场景和坐标
+func (p *Player) ApplyProfile(profile *Profile) {
+	p.RoleID = profile.RoleID
+	p.Name = profile.Name
+
+	p.attrLock.Lock()
+	p.attrs = make(map[string]int64, len(profile.Attrs))
+	for k, v := range profile.Attrs {
+		p.attrs[k] = v
+	}
+	p.attrLock.Unlock()
+
+	scene := p.world.GetScene(profile.SceneID)
+	if scene == nil || scene.IsInstance() || !InAOIBounds(scene.AoiManager, profile.X, profile.Z) {
+		return
+	}
+
+	p.lockPos()
+	p.SceneID = scene.ID
+	p.X, p.Y, p.Z, p.V = profile.X, profile.Y, profile.Z, profile.V
+	p.unlockPos()
+}
+
+// SaveProfile 保存玩家的持久化数据，没有配置存储或者玩家没有账号时不保存
+// 持有 saveLock 期间生成并写入数据，定时保存和下线保存同时执行时，后写入的数据一定是更新的数据
+func (p *Player) SaveProfile() error {
+	if p.world.Profiles == nil || p.Account == "" {
+		return nil
+	}
+
+	p.saveLock.Lock()
+	defer p.saveLock.Unlock()
+
+	return p.world.Profiles.Save(p.Profile())
+}
+
+// GetAttr 获取玩家的属性，属性不存在时为 0
+func (p *Player) GetAttr(name string) int64 {
+	p.attrLock.RLock()
+	defer p.attrLock.RUnlock()
+
+	return p.attrs[name]
+}
+
+// SetAttr 设置玩家的属性
+func (p *Player) SetAttr(name string, value int64) {
+	p.attrLock.Lock()
+	defer p.attrLock.Unlock()
+
+	if p.attrs == nil {
+		p.attrs = make(map[string]int64)
+	}
+	p.attrs[name] = value
+}
+
+// SaveAllProfiles 保存全部在线玩家的持久化数据
+func (wm *WorldManager) SaveAllProfiles() {
+	for _, player := range wm.GetAllPlayers() {
+		if err := player.SaveProfile(); err != nil {
+			fmt.Printf("save profile of player pid=%d error:%s\n", player.Pid, err)
+		}
+	}
+}
+
+// StartAutoSave 按配置的间隔定时保存全部在线玩家的持久化数据，没有配置存储或者间隔为 0 时不启动
+func (wm *WorldManager) StartAutoSave() {
+	if wm.Profiles == nil || wm.Config.Storage.SaveInterval <= 0 {
+		return
+	}
+
+	wm.saveExitChan = make(chan bool)
+	interval := time.Duration(wm.Config.Storage.SaveInterval) * time.Second
+
+	go func(exitChan chan bool) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				wm.SaveAllProfiles()
+			case <-exitChan:
+				return
+			}
+		}
+	}(wm.saveExitChan)
+}
+
+// StopAutoSave 停止定时保存
+func (wm *WorldManager) StopAutoSave() {
+	if wm.saveExitChan != nil {
+		close(wm.saveExitChan)
+		wm.saveExitChan = nil
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestPlayerProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dungeon := DefaultSceneConfig()
+	dungeon.ID = 2
+	conf := DefaultWorldConfig()
+	conf.Scenes = append(conf.Scenes, dungeon)
+	world := NewWorldManager(conf)
+	if world.Profiles, err = NewFileRepository(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// 新账号没有保存的数据
+	if _, err := world.Profiles.Load("../alice"); err != ErrProfileNotFound {
+		t.Errorf("err=%v, want ErrProfileNotFound", err)
+	}
+
+	// 下线时保存场景、坐标和属性
+	player, err := world.Login(&fakeConn{}, "../alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	player.SetAttr("hp", 80)
+	firstRoleID, firstPid := player.RoleID, player.Pid
+	if err := player.Teleport(2, 300, 1, 310, 90); err != nil {
+		t.Fatal(err)
+	}
+	player.Offline()
+
+	// 再次登录时回到下线时的场景和坐标
+	player, err = world.Login(&fakeConn{}, "../alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 角色ID不变，实体ID重新分配
+	if player.RoleID != firstRoleID || player.Pid == firstPid {
+		t.Errorf("role id=%d, pid=%d", player.RoleID, player.Pid)
+	}
+	if player.SceneID != 2 || player.X != 300 || player.Z != 310 || player.V != 90 || player.GetAttr("hp") != 80 || player.Name != "../alice" {
+		t.Errorf("profile=%+v", player.Profile())
+	}
+	if world.GetScene(2).GetPlayerByPid(player.Pid) != player {
+		t.Error("player should be added to scene 2")
+	}
+
+	// 原来的场景已经不存在时留在默认场景
+	player.Offline()
+	world.RemoveScene(2)
+	player, err = world.Login(&fakeConn{}, "../alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.SceneID != world.DefaultSceneID || player.GetAttr("hp") != 80 {
+		t.Errorf("profile=%+v", player.Profile())
+	}
+
+	// 同时保存时，最后一次保存的是玩家最新的数据
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(hp int64) {
+			defer wg.Done()
+			player.SetAttr("hp", hp)
+			if err := player.SaveProfile(); err != nil {
+				t.Error(err)
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+	profile, err := world.Profiles.Load("../alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Attrs["hp"] != player.GetAttr("hp") {
+		t.Errorf("saved hp=%d, want %d", profile.Attrs["hp"], player.GetAttr("hp"))
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// 目录不存在时自动创建，再次写入时替换原来的内容，不留下临时文件
+	path := filepath.Join(dir, "a", "b", "data")
+	for _, data := range []string{"first", "second"} {
+		if err := writeFileAtomic(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data, err := ioutil.ReadFile(path); err != nil || string(data) != "second" {
+		t.Errorf("data=%q, err=%v", data, err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("tmp file should be renamed, err=%v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"math/rand"
+	"sync"
+
+	"szinx/pb"
+)
+
+// Scene 场景，每个场景有独立的地图、AOI 管理模块和玩家集合
+type Scene struct {
+	// 场景ID
+	ID int32
+
+	// 场景名称
+	Name string
+
+	// AOIManager 当前场景地图的 AOI 管理模块
+	AoiManager IAOI
+
+	// 当前场景地图的配置
+	Config *SceneConfig
+
+	// 副本使用的模板ID，不是副本时为 0
+	TemplateID int32
+
+	// 当前场景中的 Players 集合
+	Players map[int32]*Player
+
+	// 保护 Players 的锁
+	pLock sync.RWMutex
+
+	// 本帧内坐标有变化的 Players 集合
+	dirty map[int32]*Player
+
+	// 保护 dirty 的锁
+	dLock sync.Mutex
+}
+
+// NewScene 根据场景地图的配置初始化场景
+func NewScene(conf *SceneConfig) *Scene {
+	return &Scene{
+		ID:         conf.ID,
+		Name:       conf.Name,
+		AoiManager: conf.NewAOI(),
+		Config:     conf,
+		Players:    make(map[int32]*Player),
+		dirty:      make(map[int32]*Player),
+	}
+}
+
+// IsInstance 当前场景是否为副本
+func (s *Scene) IsInstance() bool {
+	return s.TemplateID != 0
+}
+
+// SpawnPos 随机选择一个出生区域，并在其中随机一个出生坐标
+func (s *Scene) SpawnPos() (x, z float32) {
+	spawn := s.Config.Spawns[rand.Intn(len(s.Config.Spawns))]
+
+	x, z = spawn.X, spawn.Z
+	if spawn.RangeX > 0 {
+		x += float32(rand.Intn(spawn.RangeX))
+	}
+	if spawn.RangeZ > 0 {
+		z += float32(rand.Intn(spawn.RangeZ))
+	}
+
+	return x, z
+}
+
+// AddPlayer 将 Player 添加到场景中
+func (s *Scene) AddPlayer(player *Player) {
+	s.pLock.Lock()
+	s.Players[player.Pid] = player
+	s.pLock.Unlock()
+
+	// 将 Player 添加到 AOIManager 中
+	s.AoiManager.Add(int(player.Pid), player.X, player.Z)
+}
+
+// RemovePlayerByPid 将 Player 从场景中删除
+func (s *Scene) RemovePlayerByPid(pid int32) {
+	// 将 Player 从 AOIManger 中移除
+	s.AoiManager.Remove(int(pid))
+
+	s.pLock.Lock()
+	delete(s.Players, pid)
+	s.pLock.Unlock()
+
+	s.dLock.Lock()
+	delete(s.dirty, pid)
+	s.dLock.Unlock()
+}
+
+// GetPlayerByPid 通过玩家ID查询场景中的player对象
+func (s *Scene) GetPlayerByPid(pid int32) (player *Player) {
+	s.pLock.RLock()
+	defer s.pLock.RUnlock()
+
+	return s.Players[pid]
+}
+
+// GetAllPlayers 获取场景中的全部玩家
+func (s *Scene) GetAllPlayers() (players []*Player) {
+	s.pLock.RLock()
+	defer s.pLock.RUnlock()
+
+	players = make([]*Player, 0, len(s.Players))
+	for _, player := range s.Players {
+		players = append(players, player)
+	}
+
+	return players
+}
+
+// PlayerCount 场景中的玩家数量
+func (s *Scene) PlayerCount() int {
+	s.pLock.RLock()
+	defer s.pLock.RUnlock()
+
+	return len(s.Players)
+}
+
+// MarkDirty 标记玩家在本帧内坐标有变化
+func (s *Scene) MarkDirty(player *Player) {
+	s.dLock.Lock()
+	defer s.dLock.Unlock()
+
+	s.dirty[player.Pid] = player
+}
+
+// Tick 执行第 frame 帧，将本帧内移动过的玩家坐标合并，给视野内的每个玩家只发送一条消息
+// deltaSync 为 true 时以坐标增量同步，否则发送完整坐标
+func (s *Scene) Tick(frame uint64, deltaSync bool) {
+	// 1.取出本帧内坐标有变化的玩家
+	s.dLock.Lock()
+	dirty := s.dirty
+	s.dirty = make(map[int32]*Player)
+	s.dLock.Unlock()
+
+	if len(dirty) == 0 {
+		return
+	}
+
+	// 2.按观察者收集其视野内所有移动过的玩家坐标
+	updates := make(map[*Player][]*pb.Player)
+	for _, player := range dirty {
+		player.posLock.RLock()
+		pbPlayer := &pb.Player{
+			Pid: player.Pid,
+			P: &pb.Position{
+				X: player.X,
+				Y: player.Y,
+				Z: player.Z,
+				V: player.V,
+			},
+		}
+		player.posLock.RUnlock()
+
+		for _, observer := range player.GetSurroundingPlayers() {
+			updates[observer] = append(updates[observer], pbPlayer)
+		}
+	}
+
+	// 3.给每个观察者发送 MsgID:206 的坐标增量，或者 MsgID:205 的完整坐标
+	for observer, players := range updates {
+		if deltaSync {
+			observer.SendMoveDeltas(s.AoiManager, players, frame)
+			continue
+		}
+
+		observer.SendMsg(205, &pb.SyncPlayer{
+			Ps: players,
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"szinx/pb"
+
+	"github.com/YungMonk/zinx/ziface"
+)
+
+// newSessionToken 生成一个随机的会话凭证
+func newSessionToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// Detach 玩家的连接断开之后，玩家保留在世界中等待恢复会话，超过 SessionGrace 秒没有恢复则下线
+// conn 不是玩家当前的连接时（会话已经在新的连接上恢复）不做任何处理，返回 false
+func (p *Player) Detach(conn ziface.IConnection) bool {
+	grace := time.Duration(p.world.Config.Auth.SessionGrace) * time.Second
+
+	p.connLock.Lock()
+	if p.Conn != conn || p.offline {
+		p.connLock.Unlock()
+		return false
+	}
+
+	// 没有开启会话恢复时立即下线
+	if grace <= 0 {
+		p.connLock.Unlock()
+		p.Offline()
+		return true
+	}
+
+	p.Conn = nil
+	p.detached = true
+	p.detachTimer = time.AfterFunc(grace, p.expireSession)
+	p.connLock.Unlock()
+
+	fmt.Printf("====> Player pid=%d is detached, waiting for resume <====\n", p.Pid)
+	return true
+}
+
+// expireSession 等待恢复会话超时，玩家下线
+func (p *Player) expireSession() {
+	p.connLock.Lock()
+	expired := p.detached && !p.offline
+	p.connLock.Unlock()
+
+	if expired {
+		fmt.Printf("====> Player pid=%d session expired <====\n", p.Pid)
+		p.Offline()
+	}
+}
+
+// Resume 使用会话凭证将新的连接绑定到原来的玩家，并重新同步玩家视野内的完整数据
+// 原来的连接还没有断开时（例如移动网络切换），关闭原来的连接
+func (wm *WorldManager) Resume(conn ziface.IConnection, session string) (*Player, error) {
+	// 1.一个连接只能登录一次
+	if _, err := conn.GetProperty("pid"); err == nil {
+		return nil, ErrAlreadyLoggedIn
+	}
+
+	// 服务器正在关闭时不再接受恢复会话
+	if wm.IsClosing() {
+		return nil, ErrServerClosing
+	}
+
+	// 2.查询会话对应的玩家
+	player := wm.GetPlayerBySession(session)
+	if player == nil || session == "" {
+		return nil, ErrSessionExpired
+	}
+
+	// 3.将新的连接绑定到玩家，并重新同步视野内的完整数据
+	if err := player.resume(conn); err != nil {
+		return nil, err
+	}
+
+	return player, nil
+}
+
+// IsDetached 玩家的连接是否已经断开，正在等待恢复会话
+func (p *Player) IsDetached() bool {
+	p.connLock.RLock()
+	defer p.connLock.RUnlock()
+
+	return p.detached
+}
+
+// resume 将新的连接绑定到玩家，告知客户端恢复成功，并重新同步玩家ID以及视野内的完整数据
+// 玩家已经下线时返回 ErrSessionExpired
+func (p *Player) resume(conn ziface.IConnection) error {
+	p.connLock.Lock()
+	if p.offline {
+		p.connLock.Unlock()
+		return ErrSessionExpired
+	}
+	if p.detachTimer != nil {
+		p.detachTimer.Stop()
+		p.detachTimer = nil
+	}
+	old := p.Conn
+	p.Conn = conn
+	p.detached = false
+	p.connLock.Unlock()
+
+	conn.SetProperty("pid", p.Pid)
+	if old != nil && old.GetTCPConnection() != nil {
+		old.GetTCPConnection().Close()
+	}
+
+	SendLoginResult(conn, p, nil)
+	p.SyncPid()
+	p.SyncSnapshot()
+
+	p.detachIfClosed(conn)
+
+	return nil
+}
+
+// connClosedKey 连接断开的 Hook 中设置的连接属性，标记连接已经断开
+const connClosedKey = "closed"
+
+// MarkConnClosed 标记连接已经断开，需要在连接断开的 Hook 中读取 pid 属性之前调用
+// 登录或者恢复会话在 Hook 读取 pid 之后才绑定玩家时，通过这个标记发现连接已经断开
+func MarkConnClosed(conn ziface.IConnection) {
+	conn.SetProperty(connClosedKey, true)
+}
+
+// detachIfClosed 绑定 pid 属性之后检查连接是否已经断开，断开的 Hook 没有找到玩家时由这里让玩家进入等待恢复会话的状态
+// 与 Hook 同时执行时 Detach 只会生效一次
+func (p *Player) detachIfClosed(conn ziface.IConnection) {
+	if _, err := conn.GetProperty(connClosedKey); err == nil {
+		p.Detach(conn)
+	}
+}
+
+// PermLevel 玩家当前的权限等级
+func (p *Player) PermLevel() int32 {
+	p.connLock.RLock()
+	defer p.connLock.RUnlock()
+
+	return p.Level
+}
+
+// SyncSnapshot 将玩家自己以及视野内所有玩家的完整坐标发送给客户端，客户端之前的状态全部作废
+func (p *Player) SyncSnapshot() {
+	// 1.之后的坐标增量重新从完整坐标开始
+	p.clearDeltaBases()
+
+	// 2.同步玩家自己的坐标 MsgID:200
+	p.BroadCastStartPosition()
+
+	// 3.同步视野内玩家（包括自己）的完整坐标 MsgID:202
+	players := p.GetSurroundingPlayers()
+	playersProtoMsg := make([]*pb.Player, 0, len(players))
+	for _, player := range players {
+		player.posLock.RLock()
+		playersProtoMsg = append(playersProtoMsg, &pb.Player{
+			Pid: player.Pid,
+			P: &pb.Position{
+				X: player.X,
+				Y: player.Y,
+				Z: player.Z,
+				V: player.V,
+			},
+		})
+		player.posLock.RUnlock()
+	}
+
+	p.SendMsg(202, &pb.SyncPlayer{
+		Ps: playersProtoMsg,
+	})
+}
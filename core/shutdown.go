@@ -0,0 +1,88 @@
+package core
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/YungMonk/zinx/ziface"
+)
+
+// shutdownNoticeMarks 关闭倒计时中还剩多少秒时发送公告
+var shutdownNoticeMarks = []int{60, 30, 10, 5, 4, 3, 2, 1}
+
+// shutdownNotices 倒计时 countdown 秒的过程中，依次发送公告时剩余的秒数，倒计时开始时发送第一次公告
+func shutdownNotices(countdown int) []int {
+	if countdown <= 0 {
+		return nil
+	}
+
+	notices := []int{countdown}
+	for _, mark := range shutdownNoticeMarks {
+		if mark < countdown {
+			notices = append(notices, mark)
+		}
+	}
+
+	return notices
+}
+
+// IsClosing 服务器是否正在关闭
+func (wm *WorldManager) IsClosing() bool {
+	return atomic.LoadInt32(&wm.closing) == 1
+}
+
+// RejectConn 服务器正在关闭时断开新建立的连接，返回连接是否被断开，需要在连接建立的 Hook 中调用
+func (wm *WorldManager) RejectConn(conn ziface.IConnection) bool {
+	if !wm.IsClosing() {
+		return false
+	}
+
+	if tcpConn := conn.GetTCPConnection(); tcpConn != nil {
+		tcpConn.Close()
+	}
+	return true
+}
+
+// Shutdown 优雅关闭世界：停止接受连接和登录，向全部玩家公告关闭倒计时，倒计时结束之后保存世界快照，
+// 再保存全部玩家的数据并让玩家下线，重复调用时直接返回
+func (wm *WorldManager) Shutdown() {
+	// 1.停止接受新的连接、登录和恢复会话
+	if !atomic.CompareAndSwapInt32(&wm.closing, 0, 1) {
+		return
+	}
+
+	// 2.向全部玩家公告关闭倒计时
+	notices := shutdownNotices(wm.Config.Shutdown.Countdown)
+	for i, remain := range notices {
+		wm.Announce(fmt.Sprintf("server will shut down in %d seconds", remain))
+
+		next := 0
+		if i+1 < len(notices) {
+			next = notices[i+1]
+		}
+		time.Sleep(time.Duration(remain-next) * time.Second)
+	}
+
+	// 3.停止帧循环以及后台的定时任务
+	wm.StopTick()
+	wm.StopAutoSave()
+	wm.StopAutoSnapshot()
+	wm.InstanceMgr.Stop()
+	if wm.WordFilter != nil {
+		wm.WordFilter.Stop()
+	}
+
+	// 4.保存世界快照，玩家下线之前保存，重启时可以使用快照恢复玩家
+	if path := wm.Config.Storage.SnapshotFile; path != "" {
+		if err := wm.SaveSnapshot(path); err != nil {
+			fmt.Printf("save world snapshot error:%s\n", err)
+		}
+	}
+
+	// 5.保存全部玩家的数据，让玩家下线并断开连接
+	for _, player := range wm.GetAllPlayers() {
+		player.Disconnect()
+	}
+	fmt.Println("[Shutdown] all players are offline")
+}
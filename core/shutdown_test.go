@@ -0,0 +1,83 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"szinx/pb"
+)
+
+func TestShutdownNotices(t *testing.T) {
+	cases := []struct {
+		countdown int
+		want      []int
+	}{
+		{0, nil},
+		{1, []int{1}},
+		{12, []int{12, 10, 5, 4, 3, 2, 1}},
+		{60, []int{60, 30, 10, 5, 4, 3, 2, 1}},
+	}
+
+	for _, c := range cases {
+		if got := shutdownNotices(c.countdown); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("countdown=%d, notices=%v, want %v", c.countdown, got, c.want)
+		}
+	}
+}
+
+func TestWorldShutdown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shutdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	conf := DefaultWorldConfig()
+	conf.Shutdown.Countdown = 1
+	conf.Storage.SnapshotFile = filepath.Join(dir, "world.snapshot")
+	world := NewWorldManager(conf)
+	if world.Profiles, err = NewFileRepository(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := &fakeConn{}
+	player, err := world.Login(conn, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if world.RejectConn(&fakeConn{}) {
+		t.Error("connection should be accepted before shutdown")
+	}
+	world.Shutdown()
+
+	// 新的连接建立时直接断开
+	if !world.RejectConn(&fakeConn{}) {
+		t.Error("connection should be rejected during shutdown")
+	}
+
+	// 玩家收到倒计时公告，不能再登录
+	notice := &pb.Chat{}
+	if !conn.last(209, notice) || notice.Scope != ChatScopeSystem {
+		t.Errorf("notice=%v", notice)
+	}
+	if _, err := world.Login(&fakeConn{}, "bob", ""); err != ErrServerClosing {
+		t.Errorf("err=%v, want ErrServerClosing", err)
+	}
+
+	// 玩家已经下线，数据已经保存
+	if len(world.GetAllPlayers()) != 0 {
+		t.Error("all players should be offline")
+	}
+	if profile, err := world.Profiles.Load("alice"); err != nil || profile.RoleID != player.RoleID {
+		t.Errorf("profile=%v, err=%v", profile, err)
+	}
+
+	// 快照在玩家下线之前保存
+	snapshot, err := LoadSnapshot(conf.Storage.SnapshotFile)
+	if err != nil || len(snapshot.Players) != 1 || snapshot.Players[0].Pid != player.Pid {
+		t.Errorf("snapshot=%v, err=%v", snapshot, err)
+	}
+}
@@ -0,0 +1,196 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// WorldSnapshot 世界在某一时刻的快照，进程崩溃之后用于恢复在线的玩家以及副本
+type WorldSnapshot struct {
+	Frame     uint64              // 保存快照时帧循环已经执行的帧数
+	SavedAt   time.Time           // 保存快照的时间
+	Instances []*InstanceSnapshot // 当时存在的副本
+	Players   []*PlayerSnapshot   // 当时在线（包括等待恢复会话）的玩家
+}
+
+// InstanceSnapshot 副本的快照
+type InstanceSnapshot struct {
+	SceneID    int32 // 副本的场景ID
+	TemplateID int32 // 副本使用的模板ID
+}
+
+// PlayerSnapshot 玩家的快照，除了持久化数据之外还包括实体ID和会话凭证，恢复之后客户端可以继续使用
+type PlayerSnapshot struct {
+	Pid        int32     // 玩家实体ID
+	Session    string    // 会话凭证
+	Level      int32     // 权限等级
+	MutedUntil time.Time // 禁言结束的时间
+	Profile    *Profile  // 玩家的持久化数据，包括所在的场景和坐标
+}
+
+// Snapshot 生成世界当前的快照
+func (wm *WorldManager) Snapshot() *WorldSnapshot {
+	snapshot := &WorldSnapshot{
+		Frame:   wm.Frame(),
+		SavedAt: time.Now(),
+	}
+
+	for _, instance := range wm.InstanceMgr.GetAllInstances() {
+		snapshot.Instances = append(snapshot.Instances, &InstanceSnapshot{
+			SceneID:    instance.Scene.ID,
+			TemplateID: instance.TemplateID,
+		})
+	}
+
+	// 持有 snapshotLock 期间玩家的坐标和场景不会改变，全部玩家的坐标和场景来自同一时刻
+	players := wm.GetAllPlayers()
+	snapshot.Players = make([]*PlayerSnapshot, 0, len(players))
+	wm.snapshotLock.Lock()
+	for _, player := range players {
+		snapshot.Players = append(snapshot.Players, &PlayerSnapshot{
+			Pid:     player.Pid,
+			Session: player.Session,
+			Level:   player.PermLevel(),
+			Profile: player.profileLocked(),
+		})
+	}
+	wm.snapshotLock.Unlock()
+
+	for i, player := range players {
+		snapshot.Players[i].MutedUntil = player.MutedUntil()
+		snapshot.Players[i].Profile.Attrs = player.copyAttrs()
+	}
+
+	return snapshot
+}
+
+// SaveSnapshot 将世界当前的快照写入临时文件之后再替换原来的文件，避免写入一半时进程退出导致快照损坏
+// 快照中有玩家的会话凭证，文件只有当前用户可以读写
+func (wm *WorldManager) SaveSnapshot(path string) error {
+	data, err := json.Marshal(wm.Snapshot())
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data, 0600)
+}
+
+// LoadSnapshot 从文件中加载世界的快照，文件不存在时返回 ErrSnapshotNotFound
+func LoadSnapshot(path string) (*WorldSnapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &WorldSnapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("parse snapshot error: %s", err)
+	}
+
+	return snapshot, nil
+}
+
+// Restore 从快照恢复副本和玩家，返回恢复的玩家数量，需要在服务器开始接受连接之前调用
+// 恢复的玩家没有连接，等待客户端恢复会话或者重新登录，超过 SessionGrace 秒没有恢复则下线
+func (wm *WorldManager) Restore(snapshot *WorldSnapshot) int {
+	// 1.恢复副本，副本模板已经从配置中删除时，副本中的玩家回到默认场景
+	for _, instance := range snapshot.Instances {
+		if _, err := wm.InstanceMgr.RestoreInstance(instance.SceneID, instance.TemplateID); err != nil {
+			fmt.Printf("restore instance %d error:%s\n", instance.SceneID, err)
+		}
+	}
+
+	// 2.恢复玩家，之后分配的实体ID不能和恢复的玩家重复
+	reserver, _ := wm.PidAlloc.(IDReserver)
+	restored := 0
+	for _, ps := range snapshot.Players {
+		if ps.Pid <= 0 || ps.Profile == nil || wm.GetPlayerByPid(ps.Pid) != nil {
+			fmt.Printf("skip invalid player pid=%d in snapshot\n", ps.Pid)
+			continue
+		}
+		if reserver != nil {
+			reserver.Reserve(int64(ps.Pid))
+		}
+
+		player := wm.restorePlayer(ps)
+		wm.AddPlayer(player)
+
+		// 3.玩家没有连接，等待恢复会话
+		player.Detach(nil)
+		restored++
+	}
+
+	return restored
+}
+
+// restorePlayer 根据快照创建玩家，原来的场景已经不存在或者坐标超出地图边界时，玩家回到默认场景的出生区域
+func (wm *WorldManager) restorePlayer(ps *PlayerSnapshot) *Player {
+	profile := ps.Profile
+	player := &Player{
+		Pid:     ps.Pid,
+		RoleID:  profile.RoleID,
+		Account: profile.Account,
+		Session: ps.Session,
+		Name:    profile.Name,
+		Speed:   wm.Config.MoveSpeed,
+		Level:   ps.Level,
+
+		lastMoveTime: time.Now(),
+		world:        wm,
+		attrs:        profile.Attrs,
+	}
+	player.chat.mutedUntil = ps.MutedUntil
+
+	scene := wm.GetScene(profile.SceneID)
+	if scene != nil && InAOIBounds(scene.AoiManager, profile.X, profile.Z) {
+		player.SceneID = scene.ID
+		player.X, player.Y, player.Z, player.V = profile.X, profile.Y, profile.Z, profile.V
+	} else {
+		scene = wm.DefaultScene()
+		player.SceneID = scene.ID
+		player.X, player.Z = scene.SpawnPos()
+	}
+
+	return player
+}
+
+// StartAutoSnapshot 按配置的间隔定时保存世界快照，没有配置快照文件或者间隔为 0 时不启动
+func (wm *WorldManager) StartAutoSnapshot() {
+	path := wm.Config.Storage.SnapshotFile
+	if path == "" || wm.Config.Storage.SnapshotInterval <= 0 {
+		return
+	}
+
+	wm.snapshotExitChan = make(chan bool)
+	interval := time.Duration(wm.Config.Storage.SnapshotInterval) * time.Second
+
+	go func(exitChan chan bool) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := wm.SaveSnapshot(path); err != nil {
+					fmt.Printf("save world snapshot error:%s\n", err)
+				}
+			case <-exitChan:
+				return
+			}
+		}
+	}(wm.snapshotExitChan)
+}
+
+// StopAutoSnapshot 停止定时保存世界快照
+func (wm *WorldManager) StopAutoSnapshot() {
+	if wm.snapshotExitChan != nil {
+		close(wm.snapshotExitChan)
+		wm.snapshotExitChan = nil
+	}
+}
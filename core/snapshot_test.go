@@ -0,0 +1,98 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWorldSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "world", "snapshot")
+
+	conf, err := LoadWorldConfig("../conf/zinx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	world := NewWorldManager(conf)
+
+	if _, err := LoadSnapshot(path); err != ErrSnapshotNotFound {
+		t.Errorf("err=%v, want ErrSnapshotNotFound", err)
+	}
+
+	// 一个玩家在默认场景，一个玩家在副本中
+	alice, err := world.Login(&fakeConn{}, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice.SetAttr("hp", 80)
+	alice.Mute(time.Minute, time.Now())
+	bob, err := world.Login(&fakeConn{}, "bob", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cave, err := world.InstanceMgr.CreateInstance(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := world.InstanceMgr.EnterInstance(cave, []*Player{bob}); err != nil {
+		t.Fatal(err)
+	}
+	if err := world.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// 进程重启之后从快照恢复
+	snapshot, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	world = NewWorldManager(conf)
+	if n := world.Restore(snapshot); n != 2 {
+		t.Fatalf("restored %d players, want 2", n)
+	}
+
+	// 玩家的实体ID、场景、坐标、属性和禁言状态不变，等待恢复会话
+	restored := world.GetPlayerByAccount("alice")
+	if restored == nil || restored.Pid != alice.Pid || restored.X != alice.X || restored.Z != alice.Z || !restored.IsDetached() {
+		t.Fatalf("player=%+v", restored)
+	}
+	if restored.GetAttr("hp") != 80 || restored.MutedUntil().IsZero() {
+		t.Errorf("attrs=%v, muted until %v", restored.copyAttrs(), restored.MutedUntil())
+	}
+	restoredBob := world.GetPlayerByAccount("bob")
+	if restoredBob == nil || restoredBob.SceneID != cave.ID || world.GetScene(cave.ID).GetPlayerByPid(bob.Pid) != restoredBob {
+		t.Fatalf("player=%+v", restoredBob)
+	}
+
+	// 之后创建的副本和分配的实体ID不会和快照中的重复
+	if next, _ := world.InstanceMgr.CreateInstance(100); next.ID <= cave.ID {
+		t.Errorf("instance id=%d, want > %d", next.ID, cave.ID)
+	}
+	if player := newTestPlayer(t, world, nil, world.DefaultScene()); player.Pid <= bob.Pid {
+		t.Errorf("pid=%d, want > %d", player.Pid, bob.Pid)
+	}
+
+	// 客户端使用原来的会话凭证恢复会话
+	conn := &fakeConn{}
+	if p, err := world.Resume(conn, alice.Session); err != nil || p != restored || restored.IsDetached() {
+		t.Errorf("player=%v, err=%v", p, err)
+	}
+
+	// 没有会话凭证的客户端重新登录之后绑定到原来的玩家
+	auth := &HMACAuthenticator{Secret: []byte("secret")}
+	world.Auth = auth
+	token := auth.Sign("bob", time.Now().Add(time.Minute))
+	if p, err := world.Login(&fakeConn{}, "bob", token); err != nil || p != restoredBob || restoredBob.IsDetached() {
+		t.Errorf("player=%v, err=%v", p, err)
+	}
+	if _, err := world.Login(&fakeConn{}, "bob", token); err != ErrAccountOnline {
+		t.Errorf("err=%v, want ErrAccountOnline", err)
+	}
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// MaxTickRate 每秒最多执行的帧数
+const MaxTickRate = 100
+
+// StartTick 按配置的频率启动世界的帧循环，TickRate 为 0 时不启动
+func (wm *WorldManager) StartTick() {
+	if wm.Config.TickRate <= 0 {
+		return
+	}
+
+	wm.tickExitChan = make(chan bool)
+	interval := time.Second / time.Duration(wm.Config.TickRate)
+
+	go func(exitChan chan bool) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				wm.Tick()
+			case <-exitChan:
+				return
+			}
+		}
+	}(wm.tickExitChan)
+}
+
+// StopTick 停止世界的帧循环
+func (wm *WorldManager) StopTick() {
+	if wm.tickExitChan != nil {
+		close(wm.tickExitChan)
+		wm.tickExitChan = nil
+	}
+}
+
+// Tick 执行一帧，依次驱动每个场景
+func (wm *WorldManager) Tick() {
+	frame := atomic.AddUint64(&wm.frame, 1)
+
+	for _, scene := range wm.GetAllScenes() {
+		scene.Tick(frame, wm.Config.DeltaSync)
+	}
+}
+
+// Frame 帧循环已经执行的帧数
+func (wm *WorldManager) Frame() uint64 {
+	return atomic.LoadUint64(&wm.frame)
+}
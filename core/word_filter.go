@@ -0,0 +1,250 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// acNode Aho-Corasick 自动机的节点
+type acNode struct {
+	next map[rune]int // 子节点 map-key=字符，value=节点下标
+	fail int          // 失配时跳转的节点下标
+	out  int          // 以当前节点结尾的最长敏感词的长度（字符数），为 0 时没有敏感词
+}
+
+// acMatcher Aho-Corasick 多模式匹配自动机，匹配时按 Unicode 大小写折叠不区分大小写
+type acMatcher struct {
+	nodes []acNode
+}
+
+// newACMatcher 根据敏感词构建自动机，敏感词和聊天内容一样先按 NFKC 规范化
+func newACMatcher(words []string) *acMatcher {
+	m := &acMatcher{
+		nodes: []acNode{{next: make(map[rune]int)}},
+	}
+
+	// 1.构建字典树
+	for _, word := range words {
+		cur, n := 0, 0
+		for _, r := range norm.NFKC.String(word) {
+			r = foldRune(r)
+			next, ok := m.nodes[cur].next[r]
+			if !ok {
+				m.nodes = append(m.nodes, acNode{next: make(map[rune]int)})
+				next = len(m.nodes) - 1
+				m.nodes[cur].next[r] = next
+			}
+			cur = next
+			n++
+		}
+		if n > m.nodes[cur].out {
+			m.nodes[cur].out = n
+		}
+	}
+
+	// 2.按层序计算失配指针，并合并失配节点上的敏感词
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for r, child := range m.nodes[cur].next {
+			fail := m.nodes[cur].fail
+			for fail != 0 && m.nodes[fail].next[r] == 0 {
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].next[r]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			if out := m.nodes[m.nodes[child].fail].out; out > m.nodes[child].out {
+				m.nodes[child].out = out
+			}
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+// Mask 将内容中匹配到的敏感词替换为 *
+func (m *acMatcher) Mask(content string) string {
+	runes := []rune(content)
+	masked := false
+
+	cur := 0
+	for i, r := range runes {
+		r = foldRune(r)
+		for cur != 0 && m.nodes[cur].next[r] == 0 {
+			cur = m.nodes[cur].fail
+		}
+		cur = m.nodes[cur].next[r]
+
+		for j := i - m.nodes[cur].out + 1; j <= i; j++ {
+			runes[j] = '*'
+			masked = true
+		}
+	}
+
+	if !masked {
+		return content
+	}
+	return string(runes)
+}
+
+// foldRune 简单大小写折叠，同一个字符的各种大小写形式（例如 K、k 和开尔文符号 K，σ、ς 和 Σ）折叠为同一个字符
+func foldRune(r rune) rune {
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < folded {
+			folded = f
+		}
+	}
+
+	return folded
+}
+
+// WordFilter 从文件中加载敏感词表，将聊天内容中的敏感词替换为 *，词表文件修改之后自动重新加载
+type WordFilter struct {
+	// 敏感词表文件的路径，每行一个敏感词，# 开头的行为注释
+	Path string
+
+	// 检查词表文件是否修改的间隔，为 0 时不自动重新加载
+	ReloadInterval time.Duration
+
+	// 当前使用的自动机，以及加载时词表文件的修改时间
+	matcher *acMatcher
+	modTime time.Time
+
+	// 保护 matcher 和 modTime 的锁
+	lock sync.RWMutex
+
+	// 通知重新加载协程退出的 channel
+	exitChan chan bool
+}
+
+// NewWordFilter 创建敏感词过滤器，reload 为检查词表文件是否修改的秒数
+func NewWordFilter(path string, reload int) *WordFilter {
+	return &WordFilter{
+		Path:           path,
+		ReloadInterval: time.Duration(reload) * time.Second,
+		matcher:        newACMatcher(nil),
+	}
+}
+
+// Filter 将聊天内容中的敏感词替换为 *
+func (wf *WordFilter) Filter(content string) (string, error) {
+	wf.lock.RLock()
+	matcher := wf.matcher
+	wf.lock.RUnlock()
+
+	return matcher.Mask(content), nil
+}
+
+// SetWords 使用一组敏感词替换当前的词表
+func (wf *WordFilter) SetWords(words []string) {
+	matcher := newACMatcher(words)
+
+	wf.lock.Lock()
+	wf.matcher = matcher
+	wf.lock.Unlock()
+}
+
+// Reload 从文件中重新加载敏感词表，加载失败时继续使用原来的词表
+func (wf *WordFilter) Reload() error {
+	file, err := os.Open(wf.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	words := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	matcher := newACMatcher(words)
+	wf.lock.Lock()
+	wf.matcher = matcher
+	wf.modTime = info.ModTime()
+	wf.lock.Unlock()
+
+	fmt.Printf("[WordFilter] load %d words from %s\n", len(words), wf.Path)
+	return nil
+}
+
+// Start 加载敏感词表，并启动检查词表文件是否修改的协程
+func (wf *WordFilter) Start() error {
+	if err := wf.Reload(); err != nil {
+		return err
+	}
+	if wf.ReloadInterval <= 0 {
+		return nil
+	}
+
+	wf.exitChan = make(chan bool)
+	go func(exitChan chan bool) {
+		ticker := time.NewTicker(wf.ReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				wf.reloadIfModified()
+			case <-exitChan:
+				return
+			}
+		}
+	}(wf.exitChan)
+
+	return nil
+}
+
+// Stop 停止检查词表文件是否修改的协程
+func (wf *WordFilter) Stop() {
+	if wf.exitChan != nil {
+		close(wf.exitChan)
+		wf.exitChan = nil
+	}
+}
+
+// reloadIfModified 词表文件的修改时间变化之后重新加载
+func (wf *WordFilter) reloadIfModified() {
+	info, err := os.Stat(wf.Path)
+	if err != nil {
+		fmt.Println("[WordFilter] stat word list error:", err)
+		return
+	}
+
+	wf.lock.RLock()
+	modified := !info.ModTime().Equal(wf.modTime)
+	wf.lock.RUnlock()
+
+	if modified {
+		if err := wf.Reload(); err != nil {
+			fmt.Println("[WordFilter] reload word list error:", err)
+		}
+	}
+}
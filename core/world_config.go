@@ -0,0 +1,465 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// SpawnConfig 玩家出生区域的配置
+type SpawnConfig struct {
+	X      float32 // 出生点平面的 x 坐标
+	Z      float32 // 出生点平面的 y 坐标
+	RangeX int     // 基于 x 坐标随机偏移的范围
+	RangeZ int     // 基于 y 坐标随机偏移的范围
+}
+
+// SceneConfig 场景地图的配置，对应 zinx.json 中 World.Scenes 的每一项
+type SceneConfig struct {
+	ID      int32         // 场景ID
+	Name    string        // 场景名称
+	MinX    int           // 区域的左边边界坐标
+	MaxX    int           // 区域的右边边界坐标
+	CntsX   int           // X 方向格子数量
+	MinY    int           // 区域的下边边界坐标
+	MaxY    int           // 区域的上边边界坐标
+	CntsY   int           // Y 方向格子数量
+	AOIMode string        // AOI 的实现方式 grid/crosslist/quadtree
+	Radius  float32       // 视野半径，为 0 时使用九宫格视野
+	Spawns  []SpawnConfig // 玩家出生区域，出生时随机选择一个
+}
+
+// ActionConfig 玩家动作的配置，对应 zinx.json 中 World.Actions 的每一项
+type ActionConfig struct {
+	ID       int32  // 动作ID
+	Name     string // 动作名称
+	Cooldown int    // 动作的冷却时间，单位毫秒
+}
+
+// PortalConfig 传送门的配置，对应 zinx.json 中 World.Portals 的每一项
+// 玩家站在传送门附近时可以使用传送门，由服务器在目标场景的出生区域中选择目的地
+type PortalConfig struct {
+	ID      int32   // 传送门ID
+	SceneID int32   // 传送门所在的场景ID
+	X       float32 // 传送门平面的 x 坐标
+	Z       float32 // 传送门平面的 y 坐标
+	Radius  float32 // 玩家距离传送门多远以内可以使用
+	Target  int32   // 目标场景ID
+}
+
+// ChatConfig 聊天限制的配置，对应 zinx.json 中的 World.Chat 节点
+type ChatConfig struct {
+	MaxLength       int     // 聊天消息的最大长度（字符数）
+	RateBurst       int     // 令牌桶的容量，即最多连续发送的消息数量
+	RatePerSecond   float64 // 令牌桶每秒恢复的令牌数量
+	DuplicateWindow int     // 多少秒内不允许重复发送相同的消息，为 0 时不限制
+	BlockURL        bool    // 是否屏蔽包含网址的消息
+	WordListFile    string  // 敏感词表文件的路径，为空时不过滤敏感词
+	WordListReload  int     // 每隔多少秒检查敏感词表文件是否修改，为 0 时不自动重新加载
+	HistorySize     int     // 场景、世界和每个频道保存的聊天记录数量，为 0 时不保存
+	HistoryPageSize int     // 上线时发送以及每次查询最多返回的聊天记录数量
+}
+
+// AuthConfig 登录认证的配置，对应 zinx.json 中的 World.Auth 节点
+type AuthConfig struct {
+	Mode         string // 认证方式 none/hmac/file
+	Secret       string // hmac 认证的密钥
+	AccountFile  string // file 认证的账号文件路径
+	LoginTimeout int    // 连接建立之后多少秒内没有登录则断开连接
+	SessionGrace int    // 断线之后玩家保留在世界中等待恢复会话的秒数，为 0 时断线立即下线
+}
+
+// StorageConfig 玩家数据存储的配置，对应 zinx.json 中的 World.Storage 节点
+type StorageConfig struct {
+	Dir          string // 保存玩家数据的目录，为空时不保存玩家数据
+	SaveInterval int    // 每隔多少秒保存一次全部在线玩家的数据，为 0 时只在下线时保存
+
+	SnapshotFile     string // 世界快照的文件路径，为空时不保存快照
+	SnapshotInterval int    // 每隔多少秒保存一次世界快照，为 0 时只在关闭服务器时保存
+}
+
+// IDConfig ID 分配的配置，对应 zinx.json 中的 World.IDs 节点
+type IDConfig struct {
+	PidFile string // 保存玩家实体ID高水位的文件路径，为空时玩家实体ID在进程重启之后重新从 1 开始
+	PidStep int    // 每次预留的玩家实体ID数量
+	NodeID  int64  // 当前服务器的节点ID，用于生成全局唯一的角色ID
+}
+
+// ShutdownConfig 优雅关闭服务器的配置，对应 zinx.json 中的 World.Shutdown 节点
+type ShutdownConfig struct {
+	Countdown int // 收到关闭信号之后，玩家下线之前公告倒计时的秒数
+	Timeout   int // 收到关闭信号之后，最多等待多少秒完成关闭，超时之后直接退出
+}
+
+// WorldConfig 世界的配置，对应 zinx.json 中的 World 节点
+type WorldConfig struct {
+	DefaultScene        int32           // 玩家上线时进入的场景ID
+	Scenes              []*SceneConfig  // 世界中所有的场景
+	Instances           []*SceneConfig  // 副本模板，进入副本时按模板创建新的场景
+	InstanceIdleTimeout int             // 副本没有玩家之后保留的秒数
+	MoveSpeed           float32         // 玩家每秒最多移动的距离
+	TickRate            int             // 每秒执行的帧数，为 0 时玩家移动立即广播
+	DeltaSync           bool            // 帧循环中是否以量化之后的坐标增量同步玩家移动
+	FullSyncInterval    int             // 以坐标增量同步时，每隔多少帧发送一次完整坐标
+	Actions             []*ActionConfig // 玩家可以做出的动作
+	Portals             []*PortalConfig // 场景之间的传送门
+	TeleportPermission  int32           // 客户端指定坐标传送需要的权限等级，普通玩家只能使用传送门
+	Chat                ChatConfig      // 聊天限制
+	DefaultPermission   int32           // 玩家上线时的权限等级，0-普通玩家，1-GM，2-管理员
+	Auth                AuthConfig      // 登录认证
+	Storage             StorageConfig   // 玩家数据存储
+	IDs                 IDConfig        // ID 分配
+	Shutdown            ShutdownConfig  // 优雅关闭服务器
+}
+
+// DefaultSceneConfig 默认的场景配置
+func DefaultSceneConfig() *SceneConfig {
+	return &SceneConfig{
+		ID:      1,
+		Name:    "town",
+		MinX:    85,
+		MaxX:    415,
+		CntsX:   10,
+		MinY:    75,
+		MaxY:    415,
+		CntsY:   20,
+		AOIMode: AOIModeGrid,
+		Radius:  0,
+		Spawns: []SpawnConfig{
+			{
+				X:      160,
+				Z:      140,
+				RangeX: 10,
+				RangeZ: 20,
+			},
+		},
+	}
+}
+
+// DefaultWorldConfig 配置文件中没有 World 节点时使用的默认配置，只有一个场景
+func DefaultWorldConfig() *WorldConfig {
+	return &WorldConfig{
+		DefaultScene:        1,
+		Scenes:              []*SceneConfig{DefaultSceneConfig()},
+		InstanceIdleTimeout: 60,
+		MoveSpeed:           20,
+		TickRate:            10,
+		DeltaSync:           true,
+		FullSyncInterval:    50,
+		Actions: []*ActionConfig{
+			{ID: 1, Name: "wave", Cooldown: 1000},
+			{ID: 2, Name: "jump", Cooldown: 500},
+			{ID: 3, Name: "attack", Cooldown: 800},
+		},
+		TeleportPermission: PermGM,
+		Chat: ChatConfig{
+			MaxLength:       200,
+			RateBurst:       5,
+			RatePerSecond:   1,
+			DuplicateWindow: 10,
+			BlockURL:        true,
+			HistorySize:     50,
+			HistoryPageSize: 20,
+		},
+		IDs: IDConfig{
+			PidStep: 1000,
+		},
+		Auth: AuthConfig{
+			Mode:         AuthModeNone,
+			LoginTimeout: 10,
+			SessionGrace: 30,
+		},
+		Shutdown: ShutdownConfig{
+			Countdown: 10,
+			Timeout:   30,
+		},
+	}
+}
+
+// LoadWorldConfig 从 zinx 的配置文件中加载世界的配置，并校验配置是否合法
+func LoadWorldConfig(path string) (*WorldConfig, error) {
+	conf := DefaultWorldConfig()
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		fmt.Println("Config file is not exists, use default world config")
+		return conf, conf.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 数组中的每一项不能在默认配置的基础上解析，否则没有填写的字段会沿用默认场景或者默认动作的值
+	// 配置文件中没有这些数组时才使用默认配置
+	scenes, actions := conf.Scenes, conf.Actions
+	conf.Scenes, conf.Actions = nil, nil
+
+	// 只解析 World 节点，其它节点由 zinx 框架解析
+	file := struct {
+		World *WorldConfig
+	}{
+		World: conf,
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse world config error: %s", err)
+	}
+
+	if conf.Scenes == nil {
+		conf.Scenes = scenes
+	}
+	if conf.Actions == nil {
+		conf.Actions = actions
+	}
+	for _, scene := range conf.Scenes {
+		scene.setDefaults()
+	}
+	for _, template := range conf.Instances {
+		template.setDefaults()
+	}
+
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// Validate 校验世界的配置
+func (c *WorldConfig) Validate() error {
+	if len(c.Scenes) == 0 {
+		return fmt.Errorf("world has no scene")
+	}
+
+	ids := make(map[int32]bool, len(c.Scenes))
+	scenes := make(map[int32]*SceneConfig, len(c.Scenes))
+	for _, scene := range c.Scenes {
+		if ids[scene.ID] {
+			return fmt.Errorf("duplicate scene id %d", scene.ID)
+		}
+		ids[scene.ID] = true
+		scenes[scene.ID] = scene
+
+		if scene.ID <= 0 || scene.ID >= InstanceIDBase {
+			return fmt.Errorf("scene id %d must be in (0, %d)", scene.ID, InstanceIDBase)
+		}
+
+		if err := scene.Validate(); err != nil {
+			return fmt.Errorf("scene %d: %s", scene.ID, err)
+		}
+	}
+
+	if !ids[c.DefaultScene] {
+		return fmt.Errorf("default scene %d is not found", c.DefaultScene)
+	}
+
+	// 副本模板ID不能与场景ID重复，创建出来的副本使用 InstanceIDBase 之后的ID
+	for _, template := range c.Instances {
+		if ids[template.ID] {
+			return fmt.Errorf("duplicate instance template id %d", template.ID)
+		}
+		ids[template.ID] = true
+
+		if template.ID <= 0 || template.ID >= InstanceIDBase {
+			return fmt.Errorf("instance template id %d must be in (0, %d)", template.ID, InstanceIDBase)
+		}
+		if err := template.Validate(); err != nil {
+			return fmt.Errorf("instance template %d: %s", template.ID, err)
+		}
+	}
+
+	if c.InstanceIdleTimeout < 0 {
+		return fmt.Errorf("instance idle timeout %d must not be negative", c.InstanceIdleTimeout)
+	}
+
+	if c.MoveSpeed <= 0 {
+		return fmt.Errorf("move speed %f must be positive", c.MoveSpeed)
+	}
+
+	if c.TickRate < 0 || c.TickRate > MaxTickRate {
+		return fmt.Errorf("tick rate %d must be in [0, %d]", c.TickRate, MaxTickRate)
+	}
+
+	if c.DeltaSync && c.FullSyncInterval <= 0 {
+		return fmt.Errorf("full sync interval %d must be positive", c.FullSyncInterval)
+	}
+
+	actions := make(map[int32]bool, len(c.Actions))
+	for _, action := range c.Actions {
+		if action.ID <= 0 {
+			return fmt.Errorf("action id %d must be positive", action.ID)
+		}
+		if actions[action.ID] {
+			return fmt.Errorf("duplicate action id %d", action.ID)
+		}
+		actions[action.ID] = true
+
+		if action.Cooldown < 0 {
+			return fmt.Errorf("action %d cooldown %d must not be negative", action.ID, action.Cooldown)
+		}
+	}
+
+	// 传送门只能连接静态场景，副本只能通过副本管理模块进入
+	portals := make(map[int32]bool, len(c.Portals))
+	for _, portal := range c.Portals {
+		if portal.ID <= 0 {
+			return fmt.Errorf("portal id %d must be positive", portal.ID)
+		}
+		if portals[portal.ID] {
+			return fmt.Errorf("duplicate portal id %d", portal.ID)
+		}
+		portals[portal.ID] = true
+
+		scene, ok := scenes[portal.SceneID]
+		if !ok {
+			return fmt.Errorf("portal %d scene %d is not found", portal.ID, portal.SceneID)
+		}
+		if portal.X < float32(scene.MinX) || portal.X > float32(scene.MaxX) ||
+			portal.Z < float32(scene.MinY) || portal.Z > float32(scene.MaxY) {
+			return fmt.Errorf("portal %d (%.1f,%.1f) is out of scene %d", portal.ID, portal.X, portal.Z, portal.SceneID)
+		}
+		if portal.Radius <= 0 {
+			return fmt.Errorf("portal %d radius %f must be positive", portal.ID, portal.Radius)
+		}
+		if _, ok := scenes[portal.Target]; !ok {
+			return fmt.Errorf("portal %d target scene %d is not found", portal.ID, portal.Target)
+		}
+	}
+
+	if c.TeleportPermission < PermPlayer || c.TeleportPermission > PermAdmin {
+		return fmt.Errorf("teleport permission %d must be in [%d, %d]", c.TeleportPermission, PermPlayer, PermAdmin)
+	}
+
+	if c.DefaultPermission < PermPlayer || c.DefaultPermission > PermAdmin {
+		return fmt.Errorf("default permission %d must be in [%d, %d]", c.DefaultPermission, PermPlayer, PermAdmin)
+	}
+
+	switch c.Auth.Mode {
+	case AuthModeNone:
+	case AuthModeHMAC:
+		if c.Auth.Secret == "" {
+			return fmt.Errorf("hmac auth secret is empty")
+		}
+	case AuthModeFile:
+		if c.Auth.AccountFile == "" {
+			return fmt.Errorf("file auth account file is empty")
+		}
+	default:
+		return fmt.Errorf("unknown auth mode %q", c.Auth.Mode)
+	}
+	if c.Auth.LoginTimeout <= 0 {
+		return fmt.Errorf("login timeout %d must be positive", c.Auth.LoginTimeout)
+	}
+	if c.Auth.SessionGrace < 0 {
+		return fmt.Errorf("session grace %d must not be negative", c.Auth.SessionGrace)
+	}
+
+	if c.IDs.PidFile != "" && c.IDs.PidStep <= 0 {
+		return fmt.Errorf("pid step %d must be positive", c.IDs.PidStep)
+	}
+	if c.IDs.NodeID < 0 || c.IDs.NodeID > MaxNodeID {
+		return fmt.Errorf("node id %d must be in [0, %d]", c.IDs.NodeID, MaxNodeID)
+	}
+
+	if c.Storage.SaveInterval < 0 {
+		return fmt.Errorf("save interval %d must not be negative", c.Storage.SaveInterval)
+	}
+	if c.Storage.SnapshotInterval < 0 {
+		return fmt.Errorf("snapshot interval %d must not be negative", c.Storage.SnapshotInterval)
+	}
+
+	if c.Shutdown.Countdown < 0 {
+		return fmt.Errorf("shutdown countdown %d must not be negative", c.Shutdown.Countdown)
+	}
+	if c.Shutdown.Timeout <= c.Shutdown.Countdown {
+		return fmt.Errorf("shutdown timeout %d must be greater than countdown %d", c.Shutdown.Timeout, c.Shutdown.Countdown)
+	}
+
+	if c.Chat.MaxLength <= 0 {
+		return fmt.Errorf("chat max length %d must be positive", c.Chat.MaxLength)
+	}
+	if c.Chat.RateBurst <= 0 || c.Chat.RatePerSecond <= 0 {
+		return fmt.Errorf("chat rate %d/%f must be positive", c.Chat.RateBurst, c.Chat.RatePerSecond)
+	}
+	if c.Chat.DuplicateWindow < 0 {
+		return fmt.Errorf("chat duplicate window %d must not be negative", c.Chat.DuplicateWindow)
+	}
+	if c.Chat.WordListReload < 0 {
+		return fmt.Errorf("word list reload interval %d must not be negative", c.Chat.WordListReload)
+	}
+	if c.Chat.HistorySize < 0 {
+		return fmt.Errorf("chat history size %d must not be negative", c.Chat.HistorySize)
+	}
+	if c.Chat.HistorySize > 0 && c.Chat.HistoryPageSize <= 0 {
+		return fmt.Errorf("chat history page size %d must be positive", c.Chat.HistoryPageSize)
+	}
+
+	return nil
+}
+
+// setDefaults 填充配置文件中没有填写的字段：默认使用九宫格 AOI，没有出生区域时出生在地图的中心
+func (c *SceneConfig) setDefaults() {
+	if c.AOIMode == "" {
+		c.AOIMode = AOIModeGrid
+	}
+	if len(c.Spawns) == 0 {
+		c.Spawns = []SpawnConfig{{
+			X: float32(c.MinX+c.MaxX) / 2,
+			Z: float32(c.MinY+c.MaxY) / 2,
+		}}
+	}
+}
+
+// Validate 校验场景地图的配置
+func (c *SceneConfig) Validate() error {
+	if c.MaxX <= c.MinX || c.MaxY <= c.MinY {
+		return fmt.Errorf("bounds x:[%d,%d] y:[%d,%d] are empty", c.MinX, c.MaxX, c.MinY, c.MaxY)
+	}
+
+	if c.CntsX <= 0 || c.CntsY <= 0 {
+		return fmt.Errorf("grid counts %dx%d must be positive", c.CntsX, c.CntsY)
+	}
+
+	// 格子的宽度和长度都是整数，格子数量必须能整除区域的大小
+	if (c.MaxX-c.MinX)%c.CntsX != 0 {
+		return fmt.Errorf("width %d is not divisible by CntsX %d", c.MaxX-c.MinX, c.CntsX)
+	}
+	if (c.MaxY-c.MinY)%c.CntsY != 0 {
+		return fmt.Errorf("length %d is not divisible by CntsY %d", c.MaxY-c.MinY, c.CntsY)
+	}
+
+	switch c.AOIMode {
+	case AOIModeGrid, AOIModeCrossList, AOIModeQuadTree:
+	default:
+		return fmt.Errorf("unknown aoi mode %q", c.AOIMode)
+	}
+
+	if c.Radius < 0 {
+		return fmt.Errorf("aoi radius %f must not be negative", c.Radius)
+	}
+
+	// 出生区域必须完整的落在地图中
+	if len(c.Spawns) == 0 {
+		return fmt.Errorf("no spawn area")
+	}
+	for _, s := range c.Spawns {
+		if s.RangeX < 0 || s.RangeZ < 0 {
+			return fmt.Errorf("spawn range %dx%d must not be negative", s.RangeX, s.RangeZ)
+		}
+		if !c.Contains(s.X, s.Z) || !c.Contains(s.X+float32(s.RangeX), s.Z+float32(s.RangeZ)) {
+			return fmt.Errorf("spawn area (%f,%f)+(%d,%d) is out of bounds", s.X, s.Z, s.RangeX, s.RangeZ)
+		}
+	}
+
+	return nil
+}
+
+// Contains 坐标是否在地图区域内
+func (c *SceneConfig) Contains(x, z float32) bool {
+	return x >= float32(c.MinX) && x < float32(c.MaxX) && z >= float32(c.MinY) && z < float32(c.MaxY)
+}
+
+// NewAOI 根据配置创建 AOI 视野管理模块
+func (c *SceneConfig) NewAOI() IAOI {
+	return NewAOI(c.AOIMode, c.MinX, c.MaxX, c.CntsX, c.MinY, c.MaxY, c.CntsY, c.Radius)
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadWorldConfig(t *testing.T) {
+	// 加载项目中的配置文件
+	conf, err := LoadWorldConfig("../conf/zinx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.DefaultScene != 1 || len(conf.Scenes) != 2 {
+		t.Errorf("conf=%+v", conf)
+	}
+	if town := conf.Scenes[0]; town.MaxX != 415 || town.CntsY != 20 {
+		t.Errorf("town=%+v", town)
+	}
+
+	// 默认配置不使用账号文件，仓库中不保存账号凭证
+	if conf.Auth.Mode != AuthModeNone || conf.Auth.AccountFile != "" {
+		t.Errorf("auth=%+v", conf.Auth)
+	}
+}
+
+func TestLoadPartialWorldConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "zinx.json")
+	data := `{"World":{
+		"Scenes":[{"ID":1, "MaxX":100, "CntsX":5, "MaxY":100, "CntsY":5}],
+		"Actions":[{"ID":7, "Name":"dance"}]
+	}}`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有填写的字段不会沿用默认场景和默认动作的值
+	conf, err := LoadWorldConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scene := conf.Scenes[0]; scene.Name != "" || scene.AOIMode != AOIModeGrid || len(scene.Spawns) != 1 || scene.Spawns[0].X != 50 {
+		t.Errorf("scene=%+v", scene)
+	}
+	if len(conf.Actions) != 1 || conf.Actions[0].Cooldown != 0 {
+		t.Errorf("actions=%+v", conf.Actions[0])
+	}
+
+	// 配置文件中没有的数组使用默认配置
+	if err := ioutil.WriteFile(path, []byte(`{"World":{"MoveSpeed":30}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if conf, err = LoadWorldConfig(path); err != nil || len(conf.Scenes) != 1 || len(conf.Actions) != 3 {
+		t.Errorf("conf=%+v, err=%v", conf, err)
+	}
+}
+
+func TestWorldConfigValidate(t *testing.T) {
+	// 格子数量不能整除区域大小
+	conf := DefaultWorldConfig()
+	conf.Scenes[0].CntsX = 7
+	if err := conf.Validate(); err == nil {
+		t.Error("CntsX=7 should be invalid")
+	}
+
+	// 出生区域超出地图
+	conf = DefaultWorldConfig()
+	conf.Scenes[0].Spawns[0].X = 410
+	if err := conf.Validate(); err == nil {
+		t.Error("spawn out of bounds should be invalid")
+	}
+
+	// 未知的 AOI 实现方式
+	conf = DefaultWorldConfig()
+	conf.Scenes[0].AOIMode = "hexagon"
+	if err := conf.Validate(); err == nil {
+		t.Error("unknown aoi mode should be invalid")
+	}
+
+	// 重复的场景ID
+	conf = DefaultWorldConfig()
+	conf.Scenes = append(conf.Scenes, DefaultSceneConfig())
+	if err := conf.Validate(); err == nil {
+		t.Error("duplicate scene id should be invalid")
+	}
+
+	// 默认场景不存在
+	conf = DefaultWorldConfig()
+	conf.DefaultScene = 3
+	if err := conf.Validate(); err == nil {
+		t.Error("missing default scene should be invalid")
+	}
+
+	// 传送门的目标场景不存在
+	conf = DefaultWorldConfig()
+	conf.Portals = []*PortalConfig{{ID: 1, SceneID: 1, X: 200, Z: 200, Radius: 5, Target: 2}}
+	if err := conf.Validate(); err == nil {
+		t.Error("missing portal target should be invalid")
+	}
+}
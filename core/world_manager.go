@@ -4,58 +4,220 @@ import "sync"
 
 // WorldManager 当前世界总管理模块
 type WorldManager struct {
-	// AOIManager 当前世界地图的 AOI 管理模块
-	AoiManager *AOIManager
+	// 世界的配置
+	Config *WorldConfig
+
+	// 世界中的场景集合 map-key=场景ID，value=场景
+	Scenes map[int32]*Scene
+
+	// 玩家上线时进入的场景ID
+	DefaultSceneID int32
+
+	// 玩家可以做出的动作 map-key=动作ID，value=动作配置
+	Actions map[int32]*ActionConfig
+
+	// 场景之间的传送门 map-key=传送门ID，value=传送门配置
+	Portals map[int32]*PortalConfig
+
+	// 保护 Scenes 的锁
+	sLock sync.RWMutex
+
+	// 副本管理模块
+	InstanceMgr *InstanceManager
+
+	// 聊天频道管理模块
+	Channels *ChannelManager
+
+	// 场景、世界和频道最近的聊天记录
+	ChatHistory *ChatHistory
+
+	// 聊天命令的注册表
+	Commands *CommandRegistry
+
+	// 每条聊天消息发送之前执行的过滤器链
+	ChatFilters FilterChain
+
+	// 敏感词过滤器，没有配置敏感词表时为 nil
+	WordFilter *WordFilter
+
+	// 帧循环已经执行的帧数
+	frame uint64
+
+	// 通知帧循环协程退出的 channel
+	tickExitChan chan bool
+
+	// 校验登录凭证的认证器
+	Auth Authenticator
+
+	// 玩家实体ID（协议中的 Pid）的分配器，每次上线分配新的实体ID
+	PidAlloc IDAllocator
+
+	// 角色ID的分配器，角色ID随玩家数据一起保存，不会改变
+	RoleIDAlloc IDAllocator
+
+	// 玩家数据的存储，没有配置存储时为 nil，玩家数据不保存
+	Profiles PlayerRepository
+
+	// 通知定时保存协程退出的 channel
+	saveExitChan chan bool
+
+	// 通知定时保存世界快照的协程退出的 channel
+	snapshotExitChan chan bool
+
+	// 服务器是否正在关闭，为 1 时不再接受登录和恢复会话
+	closing int32
+
+	// 保证同一个账号不会同时登录成功
+	loginLock sync.Mutex
+
+	// 修改玩家的场景和坐标时持有读锁，生成快照时持有写锁，快照中全部玩家的场景和坐标来自同一时刻
+	snapshotLock sync.RWMutex
 
 	// 当前全部在线的 Players 集合
 	Players map[int32]*Player
 
+	// 已登录账号的 Players 集合 map-key=账号，value=玩家
+	accounts map[string]*Player
+
+	// 可以恢复会话的 Players 集合 map-key=会话凭证，value=玩家
+	sessions map[string]*Player
+
 	// 保护 Players 的锁
 	pLock sync.RWMutex
 }
 
-// WorldMgrObj 提供一个对外的世界管理模块句柄（全局）
-var WorldMgrObj *WorldManager
-
-// 初始化世界管理模块
-func init() {
-	WorldMgrObj = &WorldManager{
-		// 创建世界
-		AoiManager: NewAOIManager(
-			AOIMINX,
-			AOIMAXX,
-			AOICNTX,
-			AOIMINY,
-			AOIMAXY,
-			AOICNTY,
-		),
+// NewWorldManager 根据世界的配置初始化世界管理模块，并创建所有的场景
+func NewWorldManager(conf *WorldConfig) *WorldManager {
+	// 加载配置时已经校验过节点ID，这里出错说明配置没有经过校验
+	roleIDAlloc, err := NewSnowflakeAllocator(conf.IDs.NodeID)
+	if err != nil {
+		panic(err)
+	}
+
+	wm := &WorldManager{
+		Config:         conf,
+		Scenes:         make(map[int32]*Scene),
+		DefaultSceneID: conf.DefaultScene,
+		Actions:        make(map[int32]*ActionConfig),
+		Portals:        make(map[int32]*PortalConfig),
+		Channels:       NewChannelManager(),
+		ChatHistory:    NewChatHistory(conf.Chat.HistorySize),
+		Commands:       NewCommandRegistry(),
+		// 没有配置认证器时任何账号都可以登录
+		Auth: &NoneAuthenticator{Level: conf.DefaultPermission},
+		// 没有配置高水位文件时玩家实体ID只在进程内不重复
+		PidAlloc:    &MemoryAllocator{},
+		RoleIDAlloc: roleIDAlloc,
 		// 初始化 Players 集合
-		Players: make(map[int32]*Player),
+		Players:  make(map[int32]*Player),
+		accounts: make(map[string]*Player),
+		sessions: make(map[string]*Player),
 	}
+
+	for _, sceneConf := range conf.Scenes {
+		wm.AddScene(NewScene(sceneConf))
+	}
+	for _, action := range conf.Actions {
+		wm.Actions[action.ID] = action
+	}
+	for _, portal := range conf.Portals {
+		wm.Portals[portal.ID] = portal
+	}
+	wm.ChatFilters, wm.WordFilter = NewChatFilterChain(conf.Chat)
+	// 频道解散之后删除频道的聊天记录
+	wm.Channels.OnEmpty = func(name string) {
+		wm.ChatHistory.Remove(HistoryKey(ChatScopeChannel, 0, name))
+	}
+	wm.InstanceMgr = NewInstanceManager(wm, conf)
+
+	return wm
+}
+
+// AddScene 注册一个场景
+func (wm *WorldManager) AddScene(scene *Scene) {
+	wm.sLock.Lock()
+	defer wm.sLock.Unlock()
+
+	wm.Scenes[scene.ID] = scene
 }
 
-// AddPlayer 添加一个 Player
+// RemoveScene 注销一个场景
+func (wm *WorldManager) RemoveScene(sceneID int32) {
+	wm.sLock.Lock()
+	defer wm.sLock.Unlock()
+
+	delete(wm.Scenes, sceneID)
+	wm.ChatHistory.Remove(HistoryKey(ChatScopeScene, sceneID, ""))
+}
+
+// GetScene 通过场景ID查询场景
+func (wm *WorldManager) GetScene(sceneID int32) *Scene {
+	wm.sLock.RLock()
+	defer wm.sLock.RUnlock()
+
+	return wm.Scenes[sceneID]
+}
+
+// GetAllScenes 获取世界中所有的场景
+func (wm *WorldManager) GetAllScenes() (scenes []*Scene) {
+	wm.sLock.RLock()
+	defer wm.sLock.RUnlock()
+
+	scenes = make([]*Scene, 0, len(wm.Scenes))
+	for _, scene := range wm.Scenes {
+		scenes = append(scenes, scene)
+	}
+
+	return scenes
+}
+
+// DefaultScene 玩家上线时进入的场景
+func (wm *WorldManager) DefaultScene() *Scene {
+	return wm.GetScene(wm.DefaultSceneID)
+}
+
+// AddPlayer 添加一个 Player，并将其放入所在的场景
 func (wm *WorldManager) AddPlayer(player *Player) {
 	wm.pLock.Lock()
 	wm.Players[player.Pid] = player
+	if player.Account != "" {
+		wm.accounts[player.Account] = player
+	}
+	if player.Session != "" {
+		wm.sessions[player.Session] = player
+	}
 	wm.pLock.Unlock()
 
-	// 将 Player 添加到 AOIManager 中
-	wm.AoiManager.AddPidToGridByPos(int(player.Pid), player.X, player.Z)
+	// 将 Player 添加到所在的场景中
+	if scene := wm.GetScene(player.SceneID); scene != nil {
+		scene.AddPlayer(player)
+	}
 }
 
-// RemovePlayerByPid 删除一个 Player
+// RemovePlayerByPid 删除一个 Player，并将其从所在的场景中移除
 func (wm *WorldManager) RemovePlayerByPid(pid int32) {
-	// 取得当前玩家
-	player := wm.Players[pid]
-
-	// 将 Player 从 AOIManger 中移除
-	wm.AoiManager.RemovePidFromGridByPos(int(pid), player.X, player.Z)
-
 	wm.pLock.Lock()
+	player, ok := wm.Players[pid]
 	delete(wm.Players, pid)
+	if ok && wm.accounts[player.Account] == player {
+		delete(wm.accounts, player.Account)
+	}
+	if ok && wm.sessions[player.Session] == player {
+		delete(wm.sessions, player.Session)
+	}
 	wm.pLock.Unlock()
 
+	if !ok {
+		return
+	}
+
+	// 离开所有加入的聊天频道
+	wm.Channels.LeaveAll(pid)
+
+	// 将 Player 从所在的场景中移除
+	if scene := wm.GetScene(player.SceneID); scene != nil {
+		scene.RemovePlayerByPid(pid)
+	}
 }
 
 // GetPlayerByPid 通过玩家ID查询player对象
@@ -66,6 +228,22 @@ func (wm *WorldManager) GetPlayerByPid(pid int32) (player *Player) {
 	return wm.Players[pid]
 }
 
+// GetPlayerByAccount 通过账号查询在线的player对象
+func (wm *WorldManager) GetPlayerByAccount(account string) (player *Player) {
+	wm.pLock.RLock()
+	defer wm.pLock.RUnlock()
+
+	return wm.accounts[account]
+}
+
+// GetPlayerBySession 通过会话凭证查询在线的player对象
+func (wm *WorldManager) GetPlayerBySession(session string) (player *Player) {
+	wm.pLock.RLock()
+	defer wm.pLock.RUnlock()
+
+	return wm.sessions[session]
+}
+
 // GetAllPlayers 获取全部在线玩家
 func (wm *WorldManager) GetAllPlayers() (players []*Player) {
 	wm.pLock.RLock()
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"szinx/pb"
+
+	"github.com/YungMonk/zinx/ziface"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestWorldManagerScenes(t *testing.T) {
+	// 两个场景使用同样的地图配置
+	dungeon := DefaultSceneConfig()
+	dungeon.ID = 2
+	dungeon.Name = "dungeon"
+	conf := DefaultWorldConfig()
+	conf.Scenes = append(conf.Scenes, dungeon)
+	world := NewWorldManager(conf)
+
+	// 在两个场景的同一个出生区域中各创建一个玩家
+	town := world.DefaultScene()
+	p1 := newTestPlayer(t, world, nil, town)
+	p2 := newTestPlayer(t, world, nil, world.GetScene(2))
+	world.AddPlayer(p1)
+	world.AddPlayer(p2)
+
+	// 不同场景中的玩家互相看不到
+	if players := p1.GetSurroundingPlayers(); len(players) != 1 || players[0] != p1 {
+		t.Errorf("town players=%v, want only p1", players)
+	}
+	if town.PlayerCount() != 1 || len(world.GetAllPlayers()) != 2 {
+		t.Errorf("town=%d, world=%d", town.PlayerCount(), len(world.GetAllPlayers()))
+	}
+
+	p2.Offline()
+	if world.GetScene(2).PlayerCount() != 0 || world.GetPlayerByPid(p2.Pid) != nil {
+		t.Error("p2 should be removed from the world")
+	}
+}
+
+func TestIndependentWorlds(t *testing.T) {
+	// 同一个进程中的两个世界互不影响
+	world1 := NewWorldManager(DefaultWorldConfig())
+	world2 := NewWorldManager(DefaultWorldConfig())
+
+	conn1, conn2 := &fakeConn{}, &fakeConn{}
+	p1, err := world1.Login(conn1, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := world2.Login(conn2, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if world1.GetPlayerByAccount("alice") != p1 || world2.GetPlayerByAccount("alice") != p2 {
+		t.Fatal("each world should have its own player")
+	}
+
+	// 一个世界的公告和下线不会影响另一个世界
+	world1.Announce("hello")
+	if conn1.count(209) != 1 || conn2.count(209) != 0 {
+		t.Errorf("world1=%d, world2=%d announcements", conn1.count(209), conn2.count(209))
+	}
+	p1.Offline()
+	if world1.GetPlayerByPid(p1.Pid) != nil || world2.GetPlayerByPid(p2.Pid) != p2 {
+		t.Error("offline should only remove the player from its own world")
+	}
+
+	// 一个世界生成快照时不会阻塞另一个世界中玩家的移动
+	world1.snapshotLock.Lock()
+	p2.UpdatePos(p2.X+1, 0, p2.Z, 0)
+	world1.snapshotLock.Unlock()
+}
+
+func TestPlayerTeleport(t *testing.T) {
+	dungeon := DefaultSceneConfig()
+	dungeon.ID = 2
+	conf := DefaultWorldConfig()
+	conf.Scenes = append(conf.Scenes, dungeon)
+	world := NewWorldManager(conf)
+
+	player := newTestPlayer(t, world, nil, world.DefaultScene())
+	world.AddPlayer(player)
+
+	// 目标坐标超出地图边界，或者目标场景不存在时，玩家保持原地不动
+	if err := player.Teleport(2, 1000, 0, 100, 0); err != ErrOutOfBounds {
+		t.Errorf("err=%v, want ErrOutOfBounds", err)
+	}
+	if err := player.Teleport(3, 200, 0, 200, 0); err != ErrSceneNotFound {
+		t.Errorf("err=%v, want ErrSceneNotFound", err)
+	}
+	if player.SceneID != 1 {
+		t.Errorf("player should stay in scene 1")
+	}
+
+	// 传送到另一个场景
+	if err := player.Teleport(2, 200, 0, 200, 0); err != nil {
+		t.Fatal(err)
+	}
+	if world.DefaultScene().PlayerCount() != 0 || world.GetScene(2).PlayerCount() != 1 {
+		t.Error("player should be moved to scene 2")
+	}
+	if players := player.GetSurroundingPlayers(); len(players) != 1 || player.X != 200 {
+		t.Errorf("players=%v, x=%f", players, player.X)
+	}
+}
+
+func TestPlayerUsePortal(t *testing.T) {
+	conf, err := LoadWorldConfig("../conf/zinx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	world := NewWorldManager(conf)
+	player := newTestPlayer(t, world, nil, world.DefaultScene())
+	world.AddPlayer(player)
+
+	// 普通玩家不能传送到指定的坐标
+	if err := player.RequestTeleport(2, 100, 0, 100, 0); err != ErrPermissionDenied || player.SceneID != 1 {
+		t.Errorf("err=%v, want ErrPermissionDenied", err)
+	}
+
+	// 距离传送门太远，或者传送门不在当前场景中
+	if err := player.UsePortal(1); err != ErrPortalTooFar {
+		t.Errorf("err=%v, want ErrPortalTooFar", err)
+	}
+	if err := player.UsePortal(2); err != ErrPortalNotFound {
+		t.Errorf("err=%v, want ErrPortalNotFound", err)
+	}
+
+	// 站在传送门附近时传送到目标场景的出生区域
+	player.UpdatePos(398, 0, 402, 0)
+	if err := player.UsePortal(1); err != nil || player.SceneID != 2 {
+		t.Fatalf("err=%v, scene=%d", err, player.SceneID)
+	}
+	if world.GetScene(2).GetPlayerByPid(player.Pid) != player {
+		t.Error("player should be moved to scene 2")
+	}
+
+	// GM 可以传送到指定的坐标，但不能直接进入副本
+	player.Level = PermGM
+	if err := player.RequestTeleport(1, 200, 0, 200, 0); err != nil || player.SceneID != 1 {
+		t.Errorf("err=%v, scene=%d", err, player.SceneID)
+	}
+	cave, err := world.InstanceMgr.CreateInstance(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := player.RequestTeleport(cave.ID, 10, 0, 10, 0); err != ErrSceneForbidden {
+		t.Errorf("err=%v, want ErrSceneForbidden", err)
+	}
+}
+
+// newTestPlayer 创建一个测试用的玩家
+func newTestPlayer(t *testing.T, world *WorldManager, conn ziface.IConnection, scene *Scene) *Player {
+	player, err := NewPlayer(world, conn, scene)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return player
+}
+
+// fakeConn 记录发送给客户端消息的连接，用于测试
+type fakeConn struct {
+	ziface.IConnection
+	msgs  []fakeMsg
+	props map[string]interface{}
+	lock  sync.Mutex
+}
+
+// fakeMsg 发送给客户端的一条消息
+type fakeMsg struct {
+	msgID uint32
+	data  []byte
+}
+
+func (c *fakeConn) SendMsg(msgID uint32, data []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.msgs = append(c.msgs, fakeMsg{msgID: msgID, data: data})
+	return nil
+}
+
+// GetTCPConnection 测试的连接没有 socket
+func (c *fakeConn) GetTCPConnection() *net.TCPConn {
+	return nil
+}
+
+func (c *fakeConn) SetProperty(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.props == nil {
+		c.props = make(map[string]interface{})
+	}
+	c.props[key] = value
+}
+
+func (c *fakeConn) GetProperty(key string) (interface{}, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	value, ok := c.props[key]
+	if !ok {
+		return nil, fmt.Errorf("the key=%s not found", key)
+	}
+	return value, nil
+}
+
+// count 统计某个 MsgID 的消息数量
+func (c *fakeConn) count(msgID uint32) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	n := 0
+	for _, msg := range c.msgs {
+		if msg.msgID == msgID {
+			n++
+		}
+	}
+	return n
+}
+
+// last 取出某个 MsgID 的最后一条消息
+func (c *fakeConn) last(msgID uint32, m proto.Message) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i := len(c.msgs) - 1; i >= 0; i-- {
+		if c.msgs[i].msgID == msgID {
+			return proto.Unmarshal(c.msgs[i].data, m) == nil
+		}
+	}
+	return false
+}
+
+func TestSceneTick(t *testing.T) {
+	conf := DefaultWorldConfig()
+	conf.DeltaSync = false
+	world := NewWorldManager(conf)
+	scene := world.DefaultScene()
+
+	conn1, conn2 := &fakeConn{}, &fakeConn{}
+	p1, p2 := newTestPlayer(t, world, conn1, scene), newTestPlayer(t, world, conn2, scene)
+	world.AddPlayer(p1)
+	world.AddPlayer(p2)
+
+	// 同一帧内两个玩家多次移动，不会立即广播
+	p1.UpdatePos(p1.X+1, 0, p1.Z, 0)
+	p1.UpdatePos(p1.X+1, 0, p1.Z, 0)
+	p2.UpdatePos(p2.X+1, 0, p2.Z, 0)
+	if conn1.count(205) != 0 || conn1.count(200) != 0 {
+		t.Fatal("moves should be batched")
+	}
+
+	// 每个玩家在一帧内只收到一条包含所有移动的消息
+	world.Tick()
+	for _, conn := range []*fakeConn{conn1, conn2} {
+		msg := &pb.SyncPlayer{}
+		if conn.count(205) != 1 || !conn.last(205, msg) || len(msg.Ps) != 2 {
+			t.Errorf("count=%d, msg=%v", conn.count(205), msg)
+		}
+	}
+
+	// 没有移动的帧不发送消息
+	world.Tick()
+	if conn1.count(205) != 1 || world.Frame() != 2 {
+		t.Errorf("count=%d, frame=%d", conn1.count(205), world.Frame())
+	}
+}
+
+func TestSceneTickDelta(t *testing.T) {
+	conf := DefaultWorldConfig()
+	conf.FullSyncInterval = 3
+	world := NewWorldManager(conf)
+	scene := world.DefaultScene()
+
+	conn1, conn2 := &fakeConn{}, &fakeConn{}
+	p1, p2 := newTestPlayer(t, world, conn1, scene), newTestPlayer(t, world, conn2, scene)
+	world.AddPlayer(p1)
+	world.AddPlayer(p2)
+
+	// 第一次同步发送完整坐标和量化参数
+	p1.UpdatePos(165, 0, 150, 0)
+	world.Tick()
+	msg := &pb.SyncMoveDelta{}
+	if !conn2.last(206, msg) || len(msg.Ds) != 1 || msg.Ds[0].P == nil || msg.Q == nil {
+		t.Fatalf("first sync should be full, msg=%v", msg)
+	}
+
+	// 之后只发送变化的字段的增量，x 方向移动 0.33 约等于 66 个量化单位
+	p1.UpdatePos(165.33, 0, 150, 0)
+	world.Tick()
+	msg = &pb.SyncMoveDelta{}
+	conn2.last(206, msg)
+	if d := msg.Ds[0]; d.P != nil || d.DX != 66 || d.DZ != 0 || msg.Q != nil {
+		t.Errorf("delta=%v", msg)
+	}
+
+	// 量化之后没有变化时不发送
+	p1.UpdatePos(165.331, 0, 150, 0)
+	world.Tick()
+	if conn2.count(206) != 2 {
+		t.Errorf("count=%d, want 2", conn2.count(206))
+	}
+
+	// 超过完整同步的间隔之后再次发送完整坐标
+	p1.UpdatePos(166, 0, 150, 0)
+	world.Tick()
+	msg = &pb.SyncMoveDelta{}
+	conn2.last(206, msg)
+	if msg.Ds[0].P == nil {
+		t.Errorf("periodic sync should be full, msg=%v", msg)
+	}
+}
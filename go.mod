@@ -5,5 +5,6 @@ go 1.14
 require (
 	github.com/YungMonk/zinx v0.0.0-20201105100203-a6bc74b9ebe9
 	github.com/golang/protobuf v1.4.3
+	golang.org/x/text v0.3.4
 	google.golang.org/protobuf v1.25.0
 )
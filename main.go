@@ -1,66 +1,193 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"szinx/apis"
 	"szinx/core"
+	"time"
 
+	"github.com/YungMonk/zinx/utils"
 	"github.com/YungMonk/zinx/ziface"
 	"github.com/YungMonk/zinx/zlog"
 	"github.com/YungMonk/zinx/znet"
 )
 
-// OnConnectionAdd 当前客户端创建连接之后执行的 Hook 函数
-func OnConnectionAdd(conn ziface.IConnection) {
-	// 创建一个Player对象
-	player := core.NewPlayer(conn)
-
-	// 给客户端发送MsgID=1的消息，同步当前的playerID给客户端
-	player.SyncPid()
-
-	// 给客户端发送MsgID=200的消息，同步当前player的位置给客户端
-	player.BroadCastStartPosition()
-
-	// 将新上线的玩家添加到世界管理模块中
-	core.WorldMgrObj.AddPlayer(player)
-
-	// 将当前连接绑定到一个Pid玩家ID的属性
-	conn.SetProperty("pid", player.Pid)
-
-	// 在当前玩家上线之后，触发同步当前玩家位置信息（告知周围玩家当前玩家已经上线）
-	player.SyncSurrounding()
-
-	fmt.Println("\n====> Player pid=", player.Pid, " is arrived ====")
+// OnConnectionAdd 生成当前客户端创建连接之后执行的 Hook 函数
+func OnConnectionAdd(world *core.WorldManager) func(conn ziface.IConnection) {
+	return func(conn ziface.IConnection) {
+		// 服务器正在关闭时不再接受新的连接
+		if world.RejectConn(conn) {
+			return
+		}
+
+		// 连接建立之后需要先登录（MsgID=14）或者恢复会话（MsgID=15），超时没有登录则断开连接
+		world.WatchLogin(conn)
+	}
 }
 
-// OnConnectionLost 当前客户端断开连接之前执行的 Hook 函数
-func OnConnectionLost(conn ziface.IConnection) {
-	// 获取当前连接绑定的玩家 ID
-	pid, _ := conn.GetProperty("pid")
-
-	// 获取当前连接周边的玩家信息
-	player := core.WorldMgrObj.Players[pid.(int32)]
+// OnConnectionLost 生成当前客户端断开连接之前执行的 Hook 函数
+func OnConnectionLost(world *core.WorldManager) func(conn ziface.IConnection) {
+	return func(conn ziface.IConnection) {
+		// 标记连接已经断开，之后才登录成功的玩家不会一直留在世界中
+		core.MarkConnClosed(conn)
+
+		// 获取当前连接绑定的玩家 ID，没有登录的连接没有玩家
+		pid, err := conn.GetProperty("pid")
+		if err != nil {
+			return
+		}
+
+		// 获取当前连接周边的玩家信息
+		player := world.GetPlayerByPid(pid.(int32))
+		if player == nil {
+			return
+		}
+
+		// 玩家保留在世界中等待恢复会话，超时之后触发玩家下线的业务
+		if player.Detach(conn) {
+			fmt.Printf("====> Player pid=%d connection lost <====", pid)
+		}
+	}
+}
 
-	// 触发玩家下线的业务
-	player.Offline()
+// restore 启动时是否从最新的世界快照恢复玩家和副本，用于进程崩溃之后重启
+var restore = flag.Bool("restore", false, "restore players and instances from the latest world snapshot")
 
-	fmt.Printf("====> Player pid=%d will offline <====", pid)
-}
+// dev 是否为开发环境，只有开发环境才允许使用不校验凭证的 none 认证
+var dev = flag.Bool("dev", false, "development mode, allow auth mode none which accepts any account without token")
 
 func main() {
+	flag.Parse()
 	zlog.SetLevel(zlog.LogDebug)
 
+	// 0.加载世界的配置，初始化世界管理模块及所有场景
+	worldConf, err := core.LoadWorldConfig(utils.GlobalObject.ConfFilePath)
+	if err != nil {
+		fmt.Println("load world config error:", err)
+		return
+	}
+	// none 认证任何账号都可以登录，必须明确指定为开发环境
+	if worldConf.Auth.Mode == core.AuthModeNone && !*dev {
+		fmt.Println("auth mode none accepts any account without token, configure hmac or file auth, or run with -dev for development")
+		return
+	}
+	world := core.NewWorldManager(worldConf)
+
+	// 按配置创建登录认证器
+	auth, err := core.NewAuthenticator(worldConf)
+	if err != nil {
+		fmt.Println("create authenticator error:", err)
+		return
+	}
+	world.Auth = auth
+
+	// 按配置创建玩家实体ID的分配器，进程重启之后实体ID也不会重复
+	pidAlloc, err := core.NewPidAllocator(worldConf.IDs)
+	if err != nil {
+		fmt.Println("create pid allocator error:", err)
+		return
+	}
+	world.PidAlloc = pidAlloc
+
+	// 配置了存储目录时保存玩家数据，并定时保存全部在线玩家的数据
+	if worldConf.Storage.Dir != "" {
+		repo, err := core.NewFileRepository(worldConf.Storage.Dir)
+		if err != nil {
+			fmt.Println("create player repository error:", err)
+			return
+		}
+		world.Profiles = repo
+		world.StartAutoSave()
+	}
+
+	// 从最新的世界快照恢复玩家和副本，恢复的玩家等待客户端恢复会话或者重新登录
+	if *restore && worldConf.Storage.SnapshotFile != "" {
+		snapshot, err := core.LoadSnapshot(worldConf.Storage.SnapshotFile)
+		switch err {
+		case nil:
+			n := world.Restore(snapshot)
+			fmt.Printf("restore %d players from snapshot saved at %s\n", n, snapshot.SavedAt)
+		case core.ErrSnapshotNotFound:
+			fmt.Println("world snapshot is not exists, skip restore")
+		default:
+			fmt.Println("load world snapshot error:", err)
+			return
+		}
+	}
+	// 定时保存世界快照
+	world.StartAutoSnapshot()
+
+	// 启动清理空闲副本的协程，以及世界的帧循环
+	world.InstanceMgr.Start()
+	world.StartTick()
+
+	// 加载敏感词表，并在词表文件修改之后自动重新加载
+	if wf := world.WordFilter; wf != nil {
+		if err := wf.Start(); err != nil {
+			fmt.Println("load word list error:", err)
+			return
+		}
+	}
+
 	// 1.创建Server句柄，使用zinx的api
 	s := znet.NewServer("[zinx.v0.5]")
 
 	// 2.注册连接 Hook 钩子函数
-	s.SetOnConnStart(OnConnectionAdd)
-	s.SetOnConnStop(OnConnectionLost)
-
-	// 3.给服务注册路由
-	s.AddRouter(2, &apis.WorldChatAPI{})
-	s.AddRouter(3, &apis.MoveAPI{})
+	s.SetOnConnStart(OnConnectionAdd(world))
+	s.SetOnConnStop(OnConnectionLost(world))
+
+	// 3.给服务注册路由，所有路由都处理同一个世界中的玩家
+	s.AddRouter(14, &apis.LoginAPI{World: world})
+	s.AddRouter(15, &apis.ResumeAPI{World: world})
+	s.AddRouter(3, &apis.MoveAPI{World: world})
+	s.AddRouter(4, &apis.TeleportAPI{World: world})
+	s.AddRouter(5, &apis.SeqMoveAPI{World: world})
+	s.AddRouter(6, &apis.ActionAPI{World: world})
+	s.AddRouter(16, &apis.InstanceAPI{World: world})
+	s.AddRouter(17, &apis.LeaveInstanceAPI{World: world})
+
+	// 所有聊天范围都由 WorldChatAPI 处理
+	chatAPI := &apis.WorldChatAPI{World: world}
+	for _, msgID := range []uint32{2, 7, 8, 9, 10, 11, 12, 13} {
+		s.AddRouter(msgID, chatAPI)
+	}
+
+	// 4.启动Server，收到关闭信号之后优雅关闭
+	s.Start()
+	waitShutdown(world)
+}
 
-	// 4.启动Server
-	s.Serve()
+// waitShutdown 等待 SIGINT/SIGTERM 信号，收到信号之后关闭世界，超过 Timeout 秒或者再次收到信号时直接退出
+// 不调用 zinx 的 Server.Stop：它在持有连接管理器的锁时关闭连接，会和连接断开的 Hook 互相等待而阻塞。
+// zinx 也没有办法从外部关闭监听的 socket，所以关闭开始之后新的连接在建立时由 RejectConn 立即断开，
+// 玩家的连接由 Shutdown 断开，监听的 socket 随进程退出关闭
+func waitShutdown(world *core.WorldManager) {
+	conf := world.Config.Shutdown
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	sig := <-sigChan
+	fmt.Printf("receive signal %s, shutting down in %d seconds\n", sig, conf.Countdown)
+
+	done := make(chan bool)
+	go func() {
+		world.Shutdown()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		fmt.Println("server is shut down")
+	case <-time.After(time.Duration(conf.Timeout) * time.Second):
+		fmt.Println("shutdown timeout, exit now")
+		os.Exit(1)
+	case sig = <-sigChan:
+		fmt.Printf("receive signal %s again, exit now\n", sig)
+		os.Exit(1)
+	}
 }
@@ -305,6 +305,7 @@ func (x *Talk) GetContent() string {
 }
 
 // MsgID=202 同步玩家的显示数据
+// MsgID=205 帧循环中批量同步视野内玩家移动之后的坐标
 type SyncPlayer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -408,6 +409,1570 @@ func (x *Player) GetP() *Position {
 	return nil
 }
 
+// MsgID=4 使用传送门，或者传送到指定场景的坐标（需要权限）
+type Teleport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SceneID  int32     `protobuf:"varint,1,opt,name=SceneID,proto3" json:"SceneID,omitempty"`   // 目标场景 ID，为 0 时表示当前场景
+	P        *Position `protobuf:"bytes,2,opt,name=P,proto3" json:"P,omitempty"`                // 目标坐标
+	PortalID int32     `protobuf:"varint,3,opt,name=PortalID,proto3" json:"PortalID,omitempty"` // 传送门 ID，不为 0 时忽略 SceneID 和 P，由服务器选择目的地
+}
+
+func (x *Teleport) Reset() {
+	*x = Teleport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Teleport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Teleport) ProtoMessage() {}
+
+func (x *Teleport) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Teleport.ProtoReflect.Descriptor instead.
+func (*Teleport) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Teleport) GetSceneID() int32 {
+	if x != nil {
+		return x.SceneID
+	}
+	return 0
+}
+
+func (x *Teleport) GetP() *Position {
+	if x != nil {
+		return x.P
+	}
+	return nil
+}
+
+func (x *Teleport) GetPortalID() int32 {
+	if x != nil {
+		return x.PortalID
+	}
+	return 0
+}
+
+// MsgID=203 传送的结果
+type TeleportResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    int32     `protobuf:"varint,1,opt,name=Code,proto3" json:"Code,omitempty"`       // 0-成功，1-场景不存在，2-坐标超出地图边界，3-场景不允许进入，4-传送门不存在，5-距离传送门太远，6-没有权限，7-副本模板不存在，8-不是副本的队伍成员，9-队伍成员不在线，10-队伍人数超过上限，11-队长拥有的副本数量超过上限，12-不在副本中，13-服务器内部错误
+	Msg     string    `protobuf:"bytes,2,opt,name=Msg,proto3" json:"Msg,omitempty"`          // 失败的原因
+	SceneID int32     `protobuf:"varint,3,opt,name=SceneID,proto3" json:"SceneID,omitempty"` // 玩家当前所在的场景 ID
+	P       *Position `protobuf:"bytes,4,opt,name=P,proto3" json:"P,omitempty"`              // 玩家当前的坐标
+}
+
+func (x *TeleportResult) Reset() {
+	*x = TeleportResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TeleportResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeleportResult) ProtoMessage() {}
+
+func (x *TeleportResult) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeleportResult.ProtoReflect.Descriptor instead.
+func (*TeleportResult) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TeleportResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *TeleportResult) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *TeleportResult) GetSceneID() int32 {
+	if x != nil {
+		return x.SceneID
+	}
+	return 0
+}
+
+func (x *TeleportResult) GetP() *Position {
+	if x != nil {
+		return x.P
+	}
+	return nil
+}
+
+// MsgID=204 移动校验失败时，服务器纠正客户端的坐标
+type MoveCorrection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code int32     `protobuf:"varint,1,opt,name=Code,proto3" json:"Code,omitempty"` // 1-坐标不合法，2-移动速度过快，3-坐标超出地图边界，4-服务器内部错误
+	Msg  string    `protobuf:"bytes,2,opt,name=Msg,proto3" json:"Msg,omitempty"`    // 纠正的原因
+	P    *Position `protobuf:"bytes,3,opt,name=P,proto3" json:"P,omitempty"`        // 服务器认可的坐标
+}
+
+func (x *MoveCorrection) Reset() {
+	*x = MoveCorrection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveCorrection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveCorrection) ProtoMessage() {}
+
+func (x *MoveCorrection) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveCorrection.ProtoReflect.Descriptor instead.
+func (*MoveCorrection) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MoveCorrection) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *MoveCorrection) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *MoveCorrection) GetP() *Position {
+	if x != nil {
+		return x.P
+	}
+	return nil
+}
+
+// MsgID=206 帧循环中批量同步视野内玩家坐标的增量
+// 增量相对服务器上一次发送（而不是客户端确认）的坐标计算，客户端没有确认消息，
+// 依赖 TCP 按顺序送达每一条 206 消息；换成可能丢包或乱序的传输方式时，客户端会一直偏离，直到下一次完整同步
+type SyncMoveDelta struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ds []*MoveDelta `protobuf:"bytes,1,rep,name=Ds,proto3" json:"Ds,omitempty"` // 本帧内移动过的玩家
+	Q  *QuantInfo   `protobuf:"bytes,2,opt,name=Q,proto3" json:"Q,omitempty"`   // 量化参数，Ds 中有完整坐标时才会发送
+}
+
+func (x *SyncMoveDelta) Reset() {
+	*x = SyncMoveDelta{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncMoveDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncMoveDelta) ProtoMessage() {}
+
+func (x *SyncMoveDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncMoveDelta.ProtoReflect.Descriptor instead.
+func (*SyncMoveDelta) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SyncMoveDelta) GetDs() []*MoveDelta {
+	if x != nil {
+		return x.Ds
+	}
+	return nil
+}
+
+func (x *SyncMoveDelta) GetQ() *QuantInfo {
+	if x != nil {
+		return x.Q
+	}
+	return nil
+}
+
+// MsgID=206 玩家坐标的增量
+// x/z 坐标按地图边界量化为 0-65535，y 坐标按 0.01 量化，角度按 0-360 量化为 0-65535
+// 只有变化的字段才会发送，没有发送的字段表示增量为 0
+type MoveDelta struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pid int32     `protobuf:"varint,1,opt,name=Pid,proto3" json:"Pid,omitempty"`
+	DX  int32     `protobuf:"zigzag32,2,opt,name=DX,proto3" json:"DX,omitempty"` // 量化之后 x 坐标相对上一次同步的增量
+	DY  int32     `protobuf:"zigzag32,3,opt,name=DY,proto3" json:"DY,omitempty"` // 量化之后 y 坐标相对上一次同步的增量
+	DZ  int32     `protobuf:"zigzag32,4,opt,name=DZ,proto3" json:"DZ,omitempty"` // 量化之后 z 坐标相对上一次同步的增量
+	DV  int32     `protobuf:"zigzag32,5,opt,name=DV,proto3" json:"DV,omitempty"` // 量化之后角度相对上一次同步的增量
+	P   *Position `protobuf:"bytes,6,opt,name=P,proto3" json:"P,omitempty"`      // 完整的坐标，有值时忽略增量，并以其量化之后的值作为新的基准
+}
+
+func (x *MoveDelta) Reset() {
+	*x = MoveDelta{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveDelta) ProtoMessage() {}
+
+func (x *MoveDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveDelta.ProtoReflect.Descriptor instead.
+func (*MoveDelta) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MoveDelta) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *MoveDelta) GetDX() int32 {
+	if x != nil {
+		return x.DX
+	}
+	return 0
+}
+
+func (x *MoveDelta) GetDY() int32 {
+	if x != nil {
+		return x.DY
+	}
+	return 0
+}
+
+func (x *MoveDelta) GetDZ() int32 {
+	if x != nil {
+		return x.DZ
+	}
+	return 0
+}
+
+func (x *MoveDelta) GetDV() int32 {
+	if x != nil {
+		return x.DV
+	}
+	return 0
+}
+
+func (x *MoveDelta) GetP() *Position {
+	if x != nil {
+		return x.P
+	}
+	return nil
+}
+
+// MsgID=206 坐标量化的参数
+type QuantInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MinX int32 `protobuf:"varint,1,opt,name=MinX,proto3" json:"MinX,omitempty"` // 地图的左边边界坐标
+	MaxX int32 `protobuf:"varint,2,opt,name=MaxX,proto3" json:"MaxX,omitempty"` // 地图的右边边界坐标
+	MinY int32 `protobuf:"varint,3,opt,name=MinY,proto3" json:"MinY,omitempty"` // 地图的下边边界坐标（对应 z 坐标）
+	MaxY int32 `protobuf:"varint,4,opt,name=MaxY,proto3" json:"MaxY,omitempty"` // 地图的上边边界坐标（对应 z 坐标）
+}
+
+func (x *QuantInfo) Reset() {
+	*x = QuantInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuantInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuantInfo) ProtoMessage() {}
+
+func (x *QuantInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuantInfo.ProtoReflect.Descriptor instead.
+func (*QuantInfo) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *QuantInfo) GetMinX() int32 {
+	if x != nil {
+		return x.MinX
+	}
+	return 0
+}
+
+func (x *QuantInfo) GetMaxX() int32 {
+	if x != nil {
+		return x.MaxX
+	}
+	return 0
+}
+
+func (x *QuantInfo) GetMinY() int32 {
+	if x != nil {
+		return x.MinY
+	}
+	return 0
+}
+
+func (x *QuantInfo) GetMaxY() int32 {
+	if x != nil {
+		return x.MaxY
+	}
+	return 0
+}
+
+// MsgID=5 带序号的移动，用于客户端预测
+type Move struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq uint32    `protobuf:"varint,1,opt,name=Seq,proto3" json:"Seq,omitempty"` // 客户端移动输入的序号，递增
+	P   *Position `protobuf:"bytes,2,opt,name=P,proto3" json:"P,omitempty"`      // 客户端预测的坐标
+}
+
+func (x *Move) Reset() {
+	*x = Move{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Move) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Move) ProtoMessage() {}
+
+func (x *Move) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Move.ProtoReflect.Descriptor instead.
+func (*Move) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Move) GetSeq() uint32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Move) GetP() *Position {
+	if x != nil {
+		return x.P
+	}
+	return nil
+}
+
+// MsgID=207 服务器处理完移动之后的确认
+type MoveAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq  uint32    `protobuf:"varint,1,opt,name=Seq,proto3" json:"Seq,omitempty"`   // 服务器最后处理的移动序号
+	Code int32     `protobuf:"varint,2,opt,name=Code,proto3" json:"Code,omitempty"` // 0-移动被接受，其它同 MoveCorrection.Code
+	P    *Position `protobuf:"bytes,3,opt,name=P,proto3" json:"P,omitempty"`        // 服务器认可的坐标
+}
+
+func (x *MoveAck) Reset() {
+	*x = MoveAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveAck) ProtoMessage() {}
+
+func (x *MoveAck) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveAck.ProtoReflect.Descriptor instead.
+func (*MoveAck) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MoveAck) GetSeq() uint32 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *MoveAck) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *MoveAck) GetP() *Position {
+	if x != nil {
+		return x.P
+	}
+	return nil
+}
+
+// MsgID=6 玩家做出的动作（表情、攻击、跳跃等）
+type Action struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActionID int32 `protobuf:"varint,1,opt,name=ActionID,proto3" json:"ActionID,omitempty"` // 动作 ID，对应配置中的动作表
+}
+
+func (x *Action) Reset() {
+	*x = Action{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Action) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Action) ProtoMessage() {}
+
+func (x *Action) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Action.ProtoReflect.Descriptor instead.
+func (*Action) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Action) GetActionID() int32 {
+	if x != nil {
+		return x.ActionID
+	}
+	return 0
+}
+
+// MsgID=208 动作被拒绝时告知客户端原因
+type ActionResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActionID int32  `protobuf:"varint,1,opt,name=ActionID,proto3" json:"ActionID,omitempty"` // 动作 ID
+	Code     int32  `protobuf:"varint,2,opt,name=Code,proto3" json:"Code,omitempty"`         // 1-动作不存在，2-动作冷却中，3-服务器内部错误
+	Msg      string `protobuf:"bytes,3,opt,name=Msg,proto3" json:"Msg,omitempty"`            // 失败的原因
+	Remain   int32  `protobuf:"varint,4,opt,name=Remain,proto3" json:"Remain,omitempty"`     // 冷却剩余的毫秒数
+}
+
+func (x *ActionResult) Reset() {
+	*x = ActionResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionResult) ProtoMessage() {}
+
+func (x *ActionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionResult.ProtoReflect.Descriptor instead.
+func (*ActionResult) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ActionResult) GetActionID() int32 {
+	if x != nil {
+		return x.ActionID
+	}
+	return 0
+}
+
+func (x *ActionResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ActionResult) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *ActionResult) GetRemain() int32 {
+	if x != nil {
+		return x.Remain
+	}
+	return 0
+}
+
+// MsgID=7 附近聊天，发送给视野内的玩家
+type Say struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content string `protobuf:"bytes,1,opt,name=Content,proto3" json:"Content,omitempty"`
+}
+
+func (x *Say) Reset() {
+	*x = Say{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Say) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Say) ProtoMessage() {}
+
+func (x *Say) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Say.ProtoReflect.Descriptor instead.
+func (*Say) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Say) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// MsgID=8 私聊，发送给指定的玩家
+type Whisper struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Target  int32  `protobuf:"varint,1,opt,name=Target,proto3" json:"Target,omitempty"` // 目标玩家 ID
+	Content string `protobuf:"bytes,2,opt,name=Content,proto3" json:"Content,omitempty"`
+}
+
+func (x *Whisper) Reset() {
+	*x = Whisper{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Whisper) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Whisper) ProtoMessage() {}
+
+func (x *Whisper) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Whisper.ProtoReflect.Descriptor instead.
+func (*Whisper) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Whisper) GetTarget() int32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *Whisper) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// MsgID=9 世界聊天，发送给全部在线的玩家
+type WorldTalk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Content string `protobuf:"bytes,1,opt,name=Content,proto3" json:"Content,omitempty"`
+}
+
+func (x *WorldTalk) Reset() {
+	*x = WorldTalk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorldTalk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorldTalk) ProtoMessage() {}
+
+func (x *WorldTalk) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorldTalk.ProtoReflect.Descriptor instead.
+func (*WorldTalk) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WorldTalk) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// MsgID=10 频道聊天，发送给加入了频道的玩家
+type ChannelTalk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string `protobuf:"bytes,1,opt,name=Channel,proto3" json:"Channel,omitempty"` // 频道名称
+	Content string `protobuf:"bytes,2,opt,name=Content,proto3" json:"Content,omitempty"`
+}
+
+func (x *ChannelTalk) Reset() {
+	*x = ChannelTalk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelTalk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelTalk) ProtoMessage() {}
+
+func (x *ChannelTalk) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelTalk.ProtoReflect.Descriptor instead.
+func (*ChannelTalk) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ChannelTalk) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *ChannelTalk) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+// MsgID=11 加入频道
+// MsgID=12 离开频道
+type ChannelOp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Channel string `protobuf:"bytes,1,opt,name=Channel,proto3" json:"Channel,omitempty"` // 频道名称
+}
+
+func (x *ChannelOp) Reset() {
+	*x = ChannelOp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelOp) ProtoMessage() {}
+
+func (x *ChannelOp) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelOp.ProtoReflect.Descriptor instead.
+func (*ChannelOp) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ChannelOp) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+// MsgID=209 带聊天范围的聊天消息
+type Chat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pid     int32  `protobuf:"varint,1,opt,name=Pid,proto3" json:"Pid,omitempty"`        // 发送者的玩家 ID
+	Scope   int32  `protobuf:"varint,2,opt,name=Scope,proto3" json:"Scope,omitempty"`    // 1-场景，2-附近，3-私聊，4-世界，5-频道，6-系统公告
+	Content string `protobuf:"bytes,3,opt,name=Content,proto3" json:"Content,omitempty"` // 聊天内容
+	Target  int32  `protobuf:"varint,4,opt,name=Target,proto3" json:"Target,omitempty"`  // 私聊的目标玩家 ID
+	Channel string `protobuf:"bytes,5,opt,name=Channel,proto3" json:"Channel,omitempty"` // 频道名称
+	Time    int64  `protobuf:"varint,6,opt,name=Time,proto3" json:"Time,omitempty"`      // 发送的时间，Unix 毫秒时间戳
+	Seq     uint64 `protobuf:"varint,7,opt,name=Seq,proto3" json:"Seq,omitempty"`        // 聊天记录中的序号，场景、世界和频道聊天才有
+}
+
+func (x *Chat) Reset() {
+	*x = Chat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Chat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chat) ProtoMessage() {}
+
+func (x *Chat) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chat.ProtoReflect.Descriptor instead.
+func (*Chat) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Chat) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Chat) GetScope() int32 {
+	if x != nil {
+		return x.Scope
+	}
+	return 0
+}
+
+func (x *Chat) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Chat) GetTarget() int32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *Chat) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *Chat) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Chat) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+// MsgID=210 聊天失败时告知发送者原因
+type ChatResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code   int32  `protobuf:"varint,1,opt,name=Code,proto3" json:"Code,omitempty"`     // 1-玩家不存在，2-没有加入频道，3-频道名称不合法，4-被禁言，5-消息过长，6-发送过快，7-重复消息，8-包含网址，9-没有聊天记录，10-服务器内部错误
+	Msg    string `protobuf:"bytes,2,opt,name=Msg,proto3" json:"Msg,omitempty"`        // 失败的原因
+	Remain int32  `protobuf:"varint,3,opt,name=Remain,proto3" json:"Remain,omitempty"` // 被禁言时剩余的毫秒数
+}
+
+func (x *ChatResult) Reset() {
+	*x = ChatResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatResult) ProtoMessage() {}
+
+func (x *ChatResult) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatResult.ProtoReflect.Descriptor instead.
+func (*ChatResult) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ChatResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ChatResult) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *ChatResult) GetRemain() int32 {
+	if x != nil {
+		return x.Remain
+	}
+	return 0
+}
+
+// MsgID=13 查询聊天记录，用于向前翻页
+type ChatHistoryQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scope   int32  `protobuf:"varint,1,opt,name=Scope,proto3" json:"Scope,omitempty"`    // 1-当前场景，4-世界，5-频道
+	Channel string `protobuf:"bytes,2,opt,name=Channel,proto3" json:"Channel,omitempty"` // 频道名称
+	Before  uint64 `protobuf:"varint,3,opt,name=Before,proto3" json:"Before,omitempty"`  // 只查询序号小于 Before 的记录，为 0 时查询最新的记录
+	Limit   int32  `protobuf:"varint,4,opt,name=Limit,proto3" json:"Limit,omitempty"`    // 最多返回的记录数量
+}
+
+func (x *ChatHistoryQuery) Reset() {
+	*x = ChatHistoryQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatHistoryQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatHistoryQuery) ProtoMessage() {}
+
+func (x *ChatHistoryQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatHistoryQuery.ProtoReflect.Descriptor instead.
+func (*ChatHistoryQuery) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ChatHistoryQuery) GetScope() int32 {
+	if x != nil {
+		return x.Scope
+	}
+	return 0
+}
+
+func (x *ChatHistoryQuery) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *ChatHistoryQuery) GetBefore() uint64 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+func (x *ChatHistoryQuery) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// MsgID=211 一批聊天记录，上线、加入频道以及查询聊天记录时发送
+type ChatHistory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scope   int32   `protobuf:"varint,1,opt,name=Scope,proto3" json:"Scope,omitempty"`    // 1-当前场景，4-世界，5-频道
+	Channel string  `protobuf:"bytes,2,opt,name=Channel,proto3" json:"Channel,omitempty"` // 频道名称
+	Msgs    []*Chat `protobuf:"bytes,3,rep,name=Msgs,proto3" json:"Msgs,omitempty"`       // 聊天记录，按序号从旧到新排列
+	More    bool    `protobuf:"varint,4,opt,name=More,proto3" json:"More,omitempty"`      // 是否还有更早的记录
+}
+
+func (x *ChatHistory) Reset() {
+	*x = ChatHistory{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatHistory) ProtoMessage() {}
+
+func (x *ChatHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatHistory.ProtoReflect.Descriptor instead.
+func (*ChatHistory) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ChatHistory) GetScope() int32 {
+	if x != nil {
+		return x.Scope
+	}
+	return 0
+}
+
+func (x *ChatHistory) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *ChatHistory) GetMsgs() []*Chat {
+	if x != nil {
+		return x.Msgs
+	}
+	return nil
+}
+
+func (x *ChatHistory) GetMore() bool {
+	if x != nil {
+		return x.More
+	}
+	return false
+}
+
+// MsgID=212 聊天中以 / 开头的命令的执行结果，只发送给执行命令的玩家
+type CommandResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Command string `protobuf:"bytes,1,opt,name=Command,proto3" json:"Command,omitempty"` // 命令名称
+	Code    int32  `protobuf:"varint,2,opt,name=Code,proto3" json:"Code,omitempty"`      // 0-成功，1-命令不存在，2-权限不足，3-参数错误，4-执行失败
+	Msg     string `protobuf:"bytes,3,opt,name=Msg,proto3" json:"Msg,omitempty"`         // 执行结果或者失败的原因
+}
+
+func (x *CommandResult) Reset() {
+	*x = CommandResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResult) ProtoMessage() {}
+
+func (x *CommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResult.ProtoReflect.Descriptor instead.
+func (*CommandResult) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CommandResult) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *CommandResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *CommandResult) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+// MsgID=14 登录，连接建立之后必须先登录才能发送其它消息
+type Login struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account string `protobuf:"bytes,1,opt,name=Account,proto3" json:"Account,omitempty"` // 账号
+	Token   string `protobuf:"bytes,2,opt,name=Token,proto3" json:"Token,omitempty"`     // 登录凭证
+}
+
+func (x *Login) Reset() {
+	*x = Login{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Login) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Login) ProtoMessage() {}
+
+func (x *Login) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Login.ProtoReflect.Descriptor instead.
+func (*Login) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Login) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *Login) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// MsgID=213 登录或者恢复会话的结果，其它消息在登录之前发送时也会返回未登录
+type LoginResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    int32  `protobuf:"varint,1,opt,name=Code,proto3" json:"Code,omitempty"`      // 0-成功，1-认证失败，2-账号已在线，3-未登录，4-重复登录，5-会话已过期，6-服务器正在关闭，7-凭证已过期，8-没有可以分配的玩家ID，9-服务器内部错误
+	Msg     string `protobuf:"bytes,2,opt,name=Msg,proto3" json:"Msg,omitempty"`         // 失败的原因
+	Pid     int32  `protobuf:"varint,3,opt,name=Pid,proto3" json:"Pid,omitempty"`        // 登录成功之后的玩家 ID
+	Session string `protobuf:"bytes,4,opt,name=Session,proto3" json:"Session,omitempty"` // 会话凭证，断线之后用于恢复会话
+}
+
+func (x *LoginResult) Reset() {
+	*x = LoginResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoginResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResult) ProtoMessage() {}
+
+func (x *LoginResult) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResult.ProtoReflect.Descriptor instead.
+func (*LoginResult) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LoginResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *LoginResult) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *LoginResult) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *LoginResult) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+// MsgID=15 断线重连之后恢复会话，代替登录
+type Resume struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Session string `protobuf:"bytes,1,opt,name=Session,proto3" json:"Session,omitempty"` // 登录时下发的会话凭证
+}
+
+func (x *Resume) Reset() {
+	*x = Resume{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Resume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resume) ProtoMessage() {}
+
+func (x *Resume) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resume.ProtoReflect.Descriptor instead.
+func (*Resume) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Resume) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+// MsgID=16 创建副本或者进入队伍已经创建的副本，结果通过 MsgID=203 告知客户端
+type EnterInstance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TemplateID int32   `protobuf:"varint,1,opt,name=TemplateID,proto3" json:"TemplateID,omitempty"`  // 副本模板 ID，不为 0 时创建新的副本，发送者作为队长进入副本
+	Members    []int32 `protobuf:"varint,2,rep,packed,name=Members,proto3" json:"Members,omitempty"` // 创建副本时队伍中其它玩家的 ID，之后可以使用 SceneID 进入副本
+	SceneID    int32   `protobuf:"varint,3,opt,name=SceneID,proto3" json:"SceneID,omitempty"`        // 队伍已经创建的副本场景 ID，TemplateID 为 0 时进入该副本
+}
+
+func (x *EnterInstance) Reset() {
+	*x = EnterInstance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnterInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnterInstance) ProtoMessage() {}
+
+func (x *EnterInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnterInstance.ProtoReflect.Descriptor instead.
+func (*EnterInstance) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *EnterInstance) GetTemplateID() int32 {
+	if x != nil {
+		return x.TemplateID
+	}
+	return 0
+}
+
+func (x *EnterInstance) GetMembers() []int32 {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *EnterInstance) GetSceneID() int32 {
+	if x != nil {
+		return x.SceneID
+	}
+	return 0
+}
+
+// MsgID=17 离开所在的副本，回到进入副本之前的场景和坐标，结果通过 MsgID=203 告知客户端
+type LeaveInstance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LeaveInstance) Reset() {
+	*x = LeaveInstance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_message_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaveInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveInstance) ProtoMessage() {}
+
+func (x *LeaveInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_message_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveInstance.ProtoReflect.Descriptor instead.
+func (*LeaveInstance) Descriptor() ([]byte, []int) {
+	return file_message_proto_rawDescGZIP(), []int{30}
+}
+
 var File_message_proto protoreflect.FileDescriptor
 
 var file_message_proto_rawDesc = []byte{
@@ -435,9 +2000,130 @@ var file_message_proto_rawDesc = []byte{
 	0x52, 0x02, 0x70, 0x73, 0x22, 0x36, 0x0a, 0x06, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x10,
 	0x0a, 0x03, 0x50, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x50, 0x69, 0x64,
 	0x12, 0x1a, 0x0a, 0x01, 0x50, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x62,
-	0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x01, 0x50, 0x42, 0x0b, 0x5a, 0x04,
-	0x2e, 0x3b, 0x70, 0x62, 0xaa, 0x02, 0x02, 0x50, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x01, 0x50, 0x22, 0x5c, 0x0a, 0x08,
+	0x54, 0x65, 0x6c, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x63, 0x65, 0x6e,
+	0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x53, 0x63, 0x65, 0x6e, 0x65,
+	0x49, 0x44, 0x12, 0x1a, 0x0a, 0x01, 0x50, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x70, 0x62, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x01, 0x50, 0x12, 0x1a,
+	0x0a, 0x08, 0x50, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x50, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x49, 0x44, 0x22, 0x6c, 0x0a, 0x0e, 0x54, 0x65,
+	0x6c, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4d,
+	0x73, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x63, 0x65, 0x6e, 0x65, 0x49, 0x44, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x53, 0x63, 0x65, 0x6e, 0x65, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x01,
+	0x50, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x01, 0x50, 0x22, 0x52, 0x0a, 0x0e, 0x4d, 0x6f, 0x76, 0x65,
+	0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4d, 0x73, 0x67,
+	0x12, 0x1a, 0x0a, 0x01, 0x50, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x62,
+	0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x01, 0x50, 0x22, 0x4b, 0x0a, 0x0d,
+	0x53, 0x79, 0x6e, 0x63, 0x4d, 0x6f, 0x76, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x1d, 0x0a,
+	0x02, 0x44, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x4d,
+	0x6f, 0x76, 0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x52, 0x02, 0x44, 0x73, 0x12, 0x1b, 0x0a, 0x01,
+	0x51, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x62, 0x2e, 0x51, 0x75, 0x61,
+	0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x01, 0x51, 0x22, 0x79, 0x0a, 0x09, 0x4d, 0x6f, 0x76,
+	0x65, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x50, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x03, 0x50, 0x69, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x44, 0x58, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x11, 0x52, 0x02, 0x44, 0x58, 0x12, 0x0e, 0x0a, 0x02, 0x44, 0x59, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x11, 0x52, 0x02, 0x44, 0x59, 0x12, 0x0e, 0x0a, 0x02, 0x44, 0x5a, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x11, 0x52, 0x02, 0x44, 0x5a, 0x12, 0x0e, 0x0a, 0x02, 0x44, 0x56, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x11, 0x52, 0x02, 0x44, 0x56, 0x12, 0x1a, 0x0a, 0x01, 0x50, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x01, 0x50, 0x22, 0x5b, 0x0a, 0x09, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x69, 0x6e, 0x58, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x4d, 0x69, 0x6e, 0x58, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x61, 0x78, 0x58, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x4d, 0x61, 0x78, 0x58, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x69, 0x6e,
+	0x59, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4d, 0x69, 0x6e, 0x59, 0x12, 0x12, 0x0a,
+	0x04, 0x4d, 0x61, 0x78, 0x59, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x4d, 0x61, 0x78,
+	0x59, 0x22, 0x34, 0x0a, 0x04, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x65, 0x71,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x53, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x01, 0x50,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x01, 0x50, 0x22, 0x4b, 0x0a, 0x07, 0x4d, 0x6f, 0x76, 0x65, 0x41,
+	0x63, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x03, 0x53, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x01, 0x50, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x01, 0x50, 0x22, 0x24, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x22, 0x68, 0x0a, 0x0c, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x73,
+	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4d, 0x73, 0x67, 0x12, 0x16, 0x0a, 0x06,
+	0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x52, 0x65,
+	0x6d, 0x61, 0x69, 0x6e, 0x22, 0x1f, 0x0a, 0x03, 0x53, 0x61, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x3b, 0x0a, 0x07, 0x57, 0x68, 0x69, 0x73, 0x70, 0x65, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x22, 0x25, 0x0a, 0x09, 0x57, 0x6f, 0x72, 0x6c, 0x64, 0x54, 0x61, 0x6c, 0x6b, 0x12,
+	0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x41, 0x0a, 0x0b, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x61, 0x6c, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x25, 0x0a, 0x09,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4f, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x22, 0xa0, 0x01, 0x0a, 0x04, 0x43, 0x68, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x50, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x50, 0x69, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x53,
+	0x63, 0x6f, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x65, 0x71, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x03, 0x53, 0x65, 0x71, 0x22, 0x4a, 0x0a, 0x0a, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4d, 0x73, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65,
+	0x6d, 0x61, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x52, 0x65, 0x6d, 0x61,
+	0x69, 0x6e, 0x22, 0x70, 0x0a, 0x10, 0x43, 0x68, 0x61, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x22, 0x6f, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x74, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x12, 0x1c, 0x0a, 0x04, 0x4d, 0x73, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x08, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52, 0x04, 0x4d, 0x73, 0x67,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x04, 0x4d, 0x6f, 0x72, 0x65, 0x22, 0x4f, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x4d, 0x73, 0x67, 0x22, 0x37, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x5f, 0x0a, 0x0b, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x4d, 0x73, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x50, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x03, 0x50, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x22, 0x22, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x22, 0x63, 0x0a, 0x0d, 0x45, 0x6e, 0x74, 0x65, 0x72, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x07, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x53, 0x63, 0x65, 0x6e, 0x65, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x53, 0x63, 0x65, 0x6e, 0x65, 0x49, 0x44, 0x22, 0x0f, 0x0a, 0x0d, 0x4c, 0x65, 0x61,
+	0x76, 0x65, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x0b, 0x5a, 0x04, 0x2e, 0x3b,
+	0x70, 0x62, 0xaa, 0x02, 0x02, 0x50, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -452,24 +2138,58 @@ func file_message_proto_rawDescGZIP() []byte {
 	return file_message_proto_rawDescData
 }
 
-var file_message_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_message_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_message_proto_goTypes = []interface{}{
-	(*SyncPid)(nil),    // 0: pb.SyncPid
-	(*BroadCast)(nil),  // 1: pb.BroadCast
-	(*Position)(nil),   // 2: pb.Position
-	(*Talk)(nil),       // 3: pb.Talk
-	(*SyncPlayer)(nil), // 4: pb.SyncPlayer
-	(*Player)(nil),     // 5: pb.Player
+	(*SyncPid)(nil),          // 0: pb.SyncPid
+	(*BroadCast)(nil),        // 1: pb.BroadCast
+	(*Position)(nil),         // 2: pb.Position
+	(*Talk)(nil),             // 3: pb.Talk
+	(*SyncPlayer)(nil),       // 4: pb.SyncPlayer
+	(*Player)(nil),           // 5: pb.Player
+	(*Teleport)(nil),         // 6: pb.Teleport
+	(*TeleportResult)(nil),   // 7: pb.TeleportResult
+	(*MoveCorrection)(nil),   // 8: pb.MoveCorrection
+	(*SyncMoveDelta)(nil),    // 9: pb.SyncMoveDelta
+	(*MoveDelta)(nil),        // 10: pb.MoveDelta
+	(*QuantInfo)(nil),        // 11: pb.QuantInfo
+	(*Move)(nil),             // 12: pb.Move
+	(*MoveAck)(nil),          // 13: pb.MoveAck
+	(*Action)(nil),           // 14: pb.Action
+	(*ActionResult)(nil),     // 15: pb.ActionResult
+	(*Say)(nil),              // 16: pb.Say
+	(*Whisper)(nil),          // 17: pb.Whisper
+	(*WorldTalk)(nil),        // 18: pb.WorldTalk
+	(*ChannelTalk)(nil),      // 19: pb.ChannelTalk
+	(*ChannelOp)(nil),        // 20: pb.ChannelOp
+	(*Chat)(nil),             // 21: pb.Chat
+	(*ChatResult)(nil),       // 22: pb.ChatResult
+	(*ChatHistoryQuery)(nil), // 23: pb.ChatHistoryQuery
+	(*ChatHistory)(nil),      // 24: pb.ChatHistory
+	(*CommandResult)(nil),    // 25: pb.CommandResult
+	(*Login)(nil),            // 26: pb.Login
+	(*LoginResult)(nil),      // 27: pb.LoginResult
+	(*Resume)(nil),           // 28: pb.Resume
+	(*EnterInstance)(nil),    // 29: pb.EnterInstance
+	(*LeaveInstance)(nil),    // 30: pb.LeaveInstance
 }
 var file_message_proto_depIdxs = []int32{
-	2, // 0: pb.BroadCast.P:type_name -> pb.Position
-	5, // 1: pb.SyncPlayer.ps:type_name -> pb.Player
-	2, // 2: pb.Player.P:type_name -> pb.Position
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2,  // 0: pb.BroadCast.P:type_name -> pb.Position
+	5,  // 1: pb.SyncPlayer.ps:type_name -> pb.Player
+	2,  // 2: pb.Player.P:type_name -> pb.Position
+	2,  // 3: pb.Teleport.P:type_name -> pb.Position
+	2,  // 4: pb.TeleportResult.P:type_name -> pb.Position
+	2,  // 5: pb.MoveCorrection.P:type_name -> pb.Position
+	10, // 6: pb.SyncMoveDelta.Ds:type_name -> pb.MoveDelta
+	11, // 7: pb.SyncMoveDelta.Q:type_name -> pb.QuantInfo
+	2,  // 8: pb.MoveDelta.P:type_name -> pb.Position
+	2,  // 9: pb.Move.P:type_name -> pb.Position
+	2,  // 10: pb.MoveAck.P:type_name -> pb.Position
+	21, // 11: pb.ChatHistory.Msgs:type_name -> pb.Chat
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_message_proto_init() }
@@ -550,6 +2270,306 @@ func file_message_proto_init() {
 				return nil
 			}
 		}
+		file_message_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Teleport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TeleportResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveCorrection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SyncMoveDelta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveDelta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuantInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Move); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveAck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Action); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActionResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Say); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Whisper); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorldTalk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelTalk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelOp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Chat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatHistoryQuery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Login); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoginResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Resume); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnterInstance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_message_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaveInstance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_message_proto_msgTypes[1].OneofWrappers = []interface{}{
 		(*BroadCast_Content)(nil),
@@ -562,7 +2582,7 @@ func file_message_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_message_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   0,
 		},